@@ -3,6 +3,7 @@ package block
 import (
 	"errors"
 	"log"
+	"sync"
 
 	"github.com/tokentransfer/chain/core"
 	"github.com/tokentransfer/chain/core/pb"
@@ -20,9 +21,14 @@ type Block struct {
 	TransactionHash libcore.Hash
 	StateHash       libcore.Hash
 	Timestamp       int64
+	BaseFee         int64 // the lowest fee of the transactions of the block
 
 	Transactions []libblock.TransactionWithData
 	States       []libblock.State
+
+	load     func() ([]byte, error) // reads the body of a lazy block
+	loadOnce *sync.Once
+	loadErr  error
 }
 
 func (b *Block) GetIndex() uint64 {
@@ -46,24 +52,28 @@ func (b *Block) UnmarshalBinary(data []byte) error {
 	if meta != core.CORE_BLOCK {
 		return errors.New("error block data")
 	}
-	block := msg.(*pb.Block)
+	return b.fromProto(msg.(*pb.Block))
+}
+
+func (b *Block) fromProto(block *pb.Block) error {
 	b.BlockIndex = block.BlockIndex
 	b.ParentHash = libcore.Hash(block.ParentHash)
 	b.RootHash = libcore.Hash(block.RootHash)
 	b.TransactionHash = libcore.Hash(block.TransactionHash)
 	b.StateHash = libcore.Hash(block.StateHash)
 	b.Timestamp = block.Timestamp
+	b.BaseFee = block.BaseFee
+
+	return b.setBody(block.Transactions, block.States)
+}
 
-	l := len(block.Transactions)
+func (b *Block) setBody(txList []*pb.TransactionWithData, stateList [][]byte) error {
+	l := len(txList)
 	transactions := make([]libblock.TransactionWithData, l)
+	txs := make([]TransactionWithData, l)
 	for i := 0; i < l; i++ {
-		data, err := core.Marshal(block.Transactions[i])
-		if err != nil {
-			log.Println(err)
-			return err
-		}
-		tx := &TransactionWithData{}
-		err = tx.UnmarshalBinary(data)
+		tx := &txs[i]
+		err := tx.fromProto(txList[i])
 		if err != nil {
 			log.Println(err)
 			return err
@@ -72,10 +82,10 @@ func (b *Block) UnmarshalBinary(data []byte) error {
 	}
 	b.Transactions = transactions
 
-	l = len(block.States)
+	l = len(stateList)
 	states := make([]libblock.State, l)
 	for i := 0; i < l; i++ {
-		data := block.States[i]
+		data := stateList[i]
 		state, err := ReadState(data)
 		if err != nil {
 			log.Println(err)
@@ -88,48 +98,51 @@ func (b *Block) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
-func (b *Block) MarshalBinary() ([]byte, error) {
-	block := &pb.Block{
-		BlockIndex:      b.BlockIndex,
-		ParentHash:      []byte(b.ParentHash),
-		RootHash:        []byte(b.RootHash),
-		TransactionHash: []byte(b.TransactionHash),
-		StateHash:       []byte(b.StateHash),
-		Timestamp:       b.Timestamp,
+// LoadBody reads the transactions and states of a block returned by
+// NewLazyBlock. It does nothing for other blocks.
+func (b *Block) LoadBody() error {
+	if b.loadOnce == nil {
+		return nil
 	}
-
-	l := len(b.Transactions)
-	transactions := make([]*pb.TransactionWithData, l)
-	for i := 0; i < l; i++ {
-		tx := b.Transactions[i]
-		data, err := tx.MarshalBinary()
+	b.loadOnce.Do(func() {
+		data, err := b.load()
 		if err != nil {
-			return nil, err
+			b.loadErr = err
+			return
 		}
-		_, msg, err := core.Unmarshal(data)
+		meta, msg, err := core.Unmarshal(data)
 		if err != nil {
-			return nil, err
+			b.loadErr = err
+			return
 		}
-		transactions[i] = msg.(*pb.TransactionWithData)
-	}
-	block.Transactions = transactions
-
-	l = len(b.States)
-	states := make([][]byte, l)
-	for i := 0; i < l; i++ {
-		state := b.States[i]
-		data, err := state.MarshalBinary()
-		if err != nil {
-			return nil, err
+		if meta != core.CORE_BLOCK_BODY {
+			b.loadErr = errors.New("error block body data")
+			return
 		}
-		states[i] = data
-	}
-	block.States = states
+		body := msg.(*pb.BlockBody)
+		b.loadErr = b.setBody(body.Transactions, body.States)
+		b.load = nil
+	})
+	return b.loadErr
+}
 
-	return core.Marshal(block)
+// LoadBody reads the body of b if it is a lazy block. The accessors of a lazy
+// block have no error to return, so callers which need the transactions or
+// states call LoadBody first to learn why they are missing.
+func LoadBody(b libblock.Block) error {
+	if l, ok := b.(interface{ LoadBody() error }); ok {
+		return l.LoadBody()
+	}
+	return nil
 }
 
-func (b *Block) Raw(ignoreSigningFields bool) ([]byte, error) {
+// toProto converts to the message of MarshalBinary, or of Raw if raw is set.
+func (b *Block) toProto(raw bool, ignoreSigningFields bool) (*pb.Block, error) {
+	err := b.LoadBody()
+	if err != nil {
+		return nil, err
+	}
+
 	block := &pb.Block{
 		BlockIndex:      b.BlockIndex,
 		ParentHash:      []byte(b.ParentHash),
@@ -137,21 +150,17 @@ func (b *Block) Raw(ignoreSigningFields bool) ([]byte, error) {
 		TransactionHash: []byte(b.TransactionHash),
 		StateHash:       []byte(b.StateHash),
 		Timestamp:       b.Timestamp,
+		BaseFee:         b.BaseFee,
 	}
 
 	l := len(b.Transactions)
 	transactions := make([]*pb.TransactionWithData, l)
 	for i := 0; i < l; i++ {
-		tx := b.Transactions[i]
-		data, err := tx.Raw(ignoreSigningFields)
+		td, err := transactionWithDataToProto(b.Transactions[i], raw, ignoreSigningFields)
 		if err != nil {
 			return nil, err
 		}
-		_, msg, err := core.Unmarshal(data)
-		if err != nil {
-			return nil, err
-		}
-		transactions[i] = msg.(*pb.TransactionWithData)
+		transactions[i] = td
 	}
 	block.Transactions = transactions
 
@@ -159,7 +168,13 @@ func (b *Block) Raw(ignoreSigningFields bool) ([]byte, error) {
 	states := make([][]byte, l)
 	for i := 0; i < l; i++ {
 		state := b.States[i]
-		data, err := state.Raw(ignoreSigningFields)
+		var data []byte
+		var err error
+		if raw {
+			data, err = state.Raw(ignoreSigningFields)
+		} else {
+			data, err = state.MarshalBinary()
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -167,9 +182,53 @@ func (b *Block) Raw(ignoreSigningFields bool) ([]byte, error) {
 	}
 	block.States = states
 
+	return block, nil
+}
+
+func (b *Block) MarshalBinary() ([]byte, error) {
+	block, err := b.toProto(false, false)
+	if err != nil {
+		return nil, err
+	}
+	return core.Marshal(block)
+}
+
+func (b *Block) Raw(ignoreSigningFields bool) ([]byte, error) {
+	block, err := b.toProto(true, ignoreSigningFields)
+	if err != nil {
+		return nil, err
+	}
 	return core.Marshal(block)
 }
 
+// transactionWithDataToProto converts without encoding for the transactions
+// of this package, and through their binary form for other implementations.
+func transactionWithDataToProto(tx libblock.TransactionWithData, raw bool, ignoreSigningFields bool) (*pb.TransactionWithData, error) {
+	if td, ok := tx.(*TransactionWithData); ok {
+		return td.toProto(raw, ignoreSigningFields)
+	}
+
+	var data []byte
+	var err error
+	if raw {
+		data, err = tx.Raw(ignoreSigningFields)
+	} else {
+		data, err = tx.MarshalBinary()
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	td, ok := msg.(*pb.TransactionWithData)
+	if !ok {
+		return nil, errors.New("error transaction with data")
+	}
+	return td, nil
+}
+
 func (b *Block) GetParentHash() libcore.Hash {
 	return libcore.Hash(b.ParentHash)
 }
@@ -186,7 +245,15 @@ func (b *Block) GetStateHash() libcore.Hash {
 	return libcore.Hash(b.StateHash)
 }
 
+// GetTransactions returns the transactions of the block, or none if the body
+// of a lazy block fails to load, see LoadBody.
 func (b *Block) GetTransactions() []libblock.TransactionWithData {
+	err := b.LoadBody()
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+
 	l := len(b.Transactions)
 	ret := make([]libblock.TransactionWithData, l)
 	for i := 0; i < l; i++ {
@@ -195,7 +262,15 @@ func (b *Block) GetTransactions() []libblock.TransactionWithData {
 	return ret
 }
 
+// GetStates returns the states of the block, or none if the body of a lazy
+// block fails to load, see LoadBody.
 func (b *Block) GetStates() []libblock.State {
+	err := b.LoadBody()
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+
 	l := len(b.States)
 	ret := make([]libblock.State, l)
 	for i := 0; i < l; i++ {
@@ -207,3 +282,7 @@ func (b *Block) GetStates() []libblock.State {
 func (b *Block) GetTime() int64 {
 	return b.Timestamp
 }
+
+func (b *Block) GetBaseFee() int64 {
+	return b.BaseFee
+}
@@ -2,10 +2,13 @@ package block
 
 import (
 	"encoding/hex"
+	"errors"
 	"log"
 	"testing"
 
 	"github.com/tokentransfer/chain/account"
+	"github.com/tokentransfer/chain/account/jingtum"
+	"github.com/tokentransfer/chain/core"
 	"github.com/tokentransfer/chain/crypto"
 
 	. "github.com/tokentransfer/check"
@@ -32,6 +35,166 @@ func (s *BlockSuite) TestBlob(c *C) {
 	// util.PrintJSON(">> block", b)
 }
 
+// largeBlock returns a block with n signed payments between two accounts.
+func largeBlock(n int) *Block {
+	fromKey, err := jingtum.GenerateFamilySeed("masterpassphrase")
+	if err != nil {
+		panic(err)
+	}
+	from, err := fromKey.GetAddress()
+	if err != nil {
+		panic(err)
+	}
+	toKey, err := jingtum.GenerateFamilySeed("destination")
+	if err != nil {
+		panic(err)
+	}
+	to, err := toKey.GetAddress()
+	if err != nil {
+		panic(err)
+	}
+	amount := func(v int64) core.Amount {
+		a, err := core.NewAmount(v)
+		if err != nil {
+			panic(err)
+		}
+		return *a
+	}
+
+	states := []libblock.State{
+		&AccountState{
+			State:  State{Account: from, Sequence: uint64(n), BlockIndex: 10, StateType: ACCOUNT_STATE},
+			Amount: amount(1000 - 10*int64(n)),
+		},
+		&AccountState{
+			State:  State{Account: to, BlockIndex: 10, StateType: ACCOUNT_STATE},
+			Amount: amount(10 * int64(n)),
+		},
+	}
+	b := &Block{
+		BlockIndex: 10,
+		Timestamp:  1600000000,
+		States:     states,
+	}
+	service := &crypto.CryptoService{}
+	for i := 0; i < n; i++ {
+		tx := &Transaction{
+			TransactionType: TRANSACTION,
+			Account:         from,
+			Sequence:        uint64(i + 1),
+			Amount:          amount(10),
+			Destination:     to,
+		}
+		err = service.Sign(fromKey, tx)
+		if err != nil {
+			panic(err)
+		}
+		b.Transactions = append(b.Transactions, &TransactionWithData{
+			Transaction: tx,
+			Receipt: &Receipt{
+				BlockIndex:       10,
+				TransactionIndex: uint32(i),
+				States:           states,
+			},
+			Date: 1600000000,
+		})
+	}
+	return b
+}
+
+func (s *BlockSuite) TestRoundTrip(c *C) {
+	b := largeBlock(10)
+	data, err := b.MarshalBinary()
+	c.Assert(err, IsNil)
+
+	decoded := &Block{}
+	err = decoded.UnmarshalBinary(data)
+	c.Assert(err, IsNil)
+	c.Assert(len(decoded.Transactions), Equals, 10)
+
+	again, err := decoded.MarshalBinary()
+	c.Assert(err, IsNil)
+	c.Assert(again, DeepEquals, data)
+}
+
+func (s *BlockSuite) TestLazyBlock(c *C) {
+	b := largeBlock(10)
+	data, err := b.MarshalBinary()
+	c.Assert(err, IsNil)
+
+	header := NewBlockHeader(b)
+	headerData, err := header.MarshalBinary()
+	c.Assert(err, IsNil)
+	decodedHeader := &BlockHeader{}
+	err = decodedHeader.UnmarshalBinary(headerData)
+	c.Assert(err, IsNil)
+	c.Assert(decodedHeader, DeepEquals, header)
+
+	body, err := MarshalBody(b)
+	c.Assert(err, IsNil)
+	loads := 0
+	lazy := NewLazyBlock(decodedHeader, func() ([]byte, error) {
+		loads++
+		return body, nil
+	})
+	c.Assert(lazy.GetIndex(), Equals, b.GetIndex())
+	c.Assert(loads, Equals, 0)
+
+	c.Assert(len(lazy.GetTransactions()), Equals, 10)
+	c.Assert(len(lazy.GetStates()), Equals, len(b.States))
+	c.Assert(loads, Equals, 1)
+
+	again, err := lazy.MarshalBinary()
+	c.Assert(err, IsNil)
+	c.Assert(again, DeepEquals, data)
+}
+
+func (s *BlockSuite) TestLazyBlockError(c *C) {
+	header := NewBlockHeader(&Block{BlockIndex: 1})
+	failed := errors.New("missing body")
+	loads := 0
+	lazy := NewLazyBlock(header, func() ([]byte, error) {
+		loads++
+		return nil, failed
+	})
+	c.Assert(LoadBody(lazy), Equals, failed)
+	c.Assert(lazy.GetTransactions(), IsNil)
+	c.Assert(lazy.LoadBody(), Equals, failed)
+	c.Assert(loads, Equals, 1)
+
+	_, err := lazy.MarshalBinary()
+	c.Assert(err, Equals, failed)
+	c.Assert(LoadBody(&Block{}), IsNil)
+}
+
+func BenchmarkBlockUnmarshal(b *testing.B) {
+	data, err := largeBlock(1000).MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		block := &Block{}
+		err := block.UnmarshalBinary(data)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBlockMarshal(b *testing.B) {
+	block := largeBlock(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := block.MarshalBinary()
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func generateTransaction(seq uint64, value int64, gas int64) *Transaction {
 	fromKey, err := account.GenerateFamilySeed("masterpassphrase")
 	if err != nil {
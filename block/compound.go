@@ -0,0 +1,52 @@
+package block
+
+import (
+	"errors"
+
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/core/pb"
+)
+
+// Compound is the payload of a COMPOUND transaction, which applies the
+// operations for its account, all of them or none. The operations are
+// unsigned transactions without an account or a sequence; the compound
+// transaction is signed once for all of them.
+type Compound struct {
+	Operations []*Transaction
+}
+
+func (p *Compound) UnmarshalBinary(data []byte) error {
+	meta, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if meta != core.CORE_COMPOUND {
+		return errors.New("error compound data")
+	}
+	params := msg.(*pb.Compound)
+
+	p.Operations = make([]*Transaction, len(params.Operations))
+	for i, op := range params.Operations {
+		tx := &Transaction{}
+		err := tx.fromProto(op)
+		if err != nil {
+			return err
+		}
+		p.Operations[i] = tx
+	}
+	return nil
+}
+
+func (p *Compound) MarshalBinary() ([]byte, error) {
+	operations := make([]*pb.Transaction, len(p.Operations))
+	for i, op := range p.Operations {
+		t, err := op.toProto(false)
+		if err != nil {
+			return nil, err
+		}
+		operations[i] = t
+	}
+	return core.Marshal(&pb.Compound{
+		Operations: operations,
+	})
+}
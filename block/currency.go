@@ -0,0 +1,83 @@
+package block
+
+import (
+	"errors"
+
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/core/pb"
+)
+
+// IssueCurrency is the payload of an ISSUE_CURRENCY transaction, which
+// creates the currency with the symbol, issued by the account of the
+// transaction. The total supply is a decimal string with at most Decimals
+// digits after the point.
+type IssueCurrency struct {
+	Name        string
+	Symbol      string
+	Decimals    uint32
+	TotalSupply string
+	Flags       uint32 // CURRENCY_* flags
+}
+
+func (p *IssueCurrency) UnmarshalBinary(data []byte) error {
+	meta, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if meta != core.CORE_ISSUE_CURRENCY {
+		return errors.New("error issue currency data")
+	}
+	params := msg.(*pb.IssueCurrency)
+
+	p.Name = params.Name
+	p.Symbol = params.Symbol
+	p.Decimals = params.Decimals
+	p.TotalSupply = params.TotalSupply
+	p.Flags = params.Flags
+	return nil
+}
+
+func (p *IssueCurrency) MarshalBinary() ([]byte, error) {
+	return core.Marshal(&pb.IssueCurrency{
+		Name:        p.Name,
+		Symbol:      p.Symbol,
+		Decimals:    p.Decimals,
+		TotalSupply: p.TotalSupply,
+		Flags:       p.Flags,
+	})
+}
+
+// FlagsChange is the payload of the CURRENCY_SET, TRUST_CONTROL and
+// ACCOUNT_SET transactions, which set then clear flags of a currency, a trust
+// line or an account.
+type FlagsChange struct {
+	SetFlags   uint32
+	ClearFlags uint32
+}
+
+func (p *FlagsChange) UnmarshalBinary(data []byte) error {
+	meta, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if meta != core.CORE_FLAGS_CHANGE {
+		return errors.New("error flags change data")
+	}
+	params := msg.(*pb.FlagsChange)
+
+	p.SetFlags = params.SetFlags
+	p.ClearFlags = params.ClearFlags
+	return nil
+}
+
+func (p *FlagsChange) MarshalBinary() ([]byte, error) {
+	return core.Marshal(&pb.FlagsChange{
+		SetFlags:   p.SetFlags,
+		ClearFlags: p.ClearFlags,
+	})
+}
+
+// Apply returns the flags after the change.
+func (p *FlagsChange) Apply(flags uint32) uint32 {
+	return (flags | p.SetFlags) &^ p.ClearFlags
+}
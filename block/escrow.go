@@ -0,0 +1,207 @@
+package block
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/core/pb"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+// EscrowState locks the amount of its account until it is finished to the
+// destination, or cancelled back to the account. Its sequence is the one of
+// the transaction which created it, and Operation the index of the operation
+// which created it if the transaction is compound. The times are compared
+// with the timestamp of the block, and a zero time isn't set. An escrow with
+// a hash lock is finished with the preimage of the hash, as a
+// hash-time-locked contract. A closed escrow is kept with the flag of how it
+// was closed.
+type EscrowState struct {
+	State
+
+	Destination libcore.Address
+	Amount      core.Amount
+	FinishAfter int64  // the escrow can be finished from this time
+	CancelAfter int64  // the escrow can be cancelled, and no longer finished, from this time
+	Flags       uint32 // ESCROW_* flags
+	HashLock    libcore.Hash
+	Operation   uint32
+}
+
+// Flags of EscrowState.
+const (
+	ESCROW_FINISHED  uint32 = 1 << iota // the amount went to the destination
+	ESCROW_CANCELLED                    // the amount went back to the account
+
+	ESCROW_CLOSED = ESCROW_FINISHED | ESCROW_CANCELLED
+)
+
+// GetEscrowKey returns the key of the escrow created by the transaction of
+// the account with the sequence, or by its operation if it is compound.
+func GetEscrowKey(account libcore.Address, sequence uint64, operation uint32) string {
+	return fmt.Sprintf("%s-%d-%d", account.String(), sequence, operation)
+}
+
+func (s *EscrowState) GetStateKey() string {
+	return GetEscrowKey(s.Account, s.Sequence, s.Operation)
+}
+
+func (s *EscrowState) UnmarshalBinary(data []byte) error {
+	meta, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if meta != core.CORE_ESCROW_STATE {
+		return errors.New("error state data")
+	}
+	state := msg.(*pb.EscrowState)
+
+	_, account, err := as.NewAccountFromBytes(state.Account[:])
+	if err != nil {
+		return err
+	}
+	destination, err := byteToAddress(state.Destination)
+	if err != nil {
+		return err
+	}
+	a, err := core.NewAmount(state.Amount)
+	if err != nil {
+		return err
+	}
+
+	s.StateType = libblock.StateType(core.CORE_ESCROW_STATE)
+	s.BlockIndex = state.BlockIndex
+	s.Account = account
+	s.Sequence = state.Sequence
+	s.Destination = destination
+	s.Amount = *a
+	s.FinishAfter = state.FinishAfter
+	s.CancelAfter = state.CancelAfter
+	s.Flags = state.Flags
+	s.HashLock = libcore.Hash(state.HashLock)
+	s.Operation = state.Operation
+	return nil
+}
+
+func (s *EscrowState) toProto(ignoreVariableFields bool) (*pb.EscrowState, error) {
+	a, err := s.Account.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	destination, err := addressToByte(s.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &pb.EscrowState{
+		StateType:   uint32(core.CORE_ESCROW_STATE),
+		Account:     a,
+		Sequence:    s.Sequence,
+		Destination: destination,
+		Amount:      s.Amount.String(),
+		FinishAfter: s.FinishAfter,
+		CancelAfter: s.CancelAfter,
+		Flags:       s.Flags,
+		HashLock:    []byte(s.HashLock),
+		Operation:   s.Operation,
+	}
+	if !ignoreVariableFields {
+		state.BlockIndex = s.BlockIndex
+	}
+	return state, nil
+}
+
+func (s *EscrowState) MarshalBinary() ([]byte, error) {
+	state, err := s.toProto(false)
+	if err != nil {
+		return nil, err
+	}
+	return core.Marshal(state)
+}
+
+func (s *EscrowState) Raw(ignoreSigningFields bool) ([]byte, error) {
+	state, err := s.toProto(true)
+	if err != nil {
+		return nil, err
+	}
+	return core.Marshal(state)
+}
+
+// EscrowCreate is the payload of an ESCROW_CREATE transaction, which locks
+// its amount for its destination.
+type EscrowCreate struct {
+	FinishAfter int64
+	CancelAfter int64
+	HashLock    libcore.Hash
+}
+
+func (p *EscrowCreate) UnmarshalBinary(data []byte) error {
+	meta, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if meta != core.CORE_ESCROW_CREATE {
+		return errors.New("error escrow create data")
+	}
+	params := msg.(*pb.EscrowCreate)
+
+	p.FinishAfter = params.FinishAfter
+	p.CancelAfter = params.CancelAfter
+	p.HashLock = libcore.Hash(params.HashLock)
+	return nil
+}
+
+func (p *EscrowCreate) MarshalBinary() ([]byte, error) {
+	return core.Marshal(&pb.EscrowCreate{
+		FinishAfter: p.FinishAfter,
+		CancelAfter: p.CancelAfter,
+		HashLock:    []byte(p.HashLock),
+	})
+}
+
+// EscrowReference is the payload of the ESCROW_FINISH and ESCROW_CANCEL
+// transactions, naming the escrow by its account, sequence and operation.
+// Preimage finishes an escrow with a hash lock.
+type EscrowReference struct {
+	Owner     libcore.Address
+	Sequence  uint64
+	Operation uint32
+	Preimage  libcore.Bytes
+}
+
+func (p *EscrowReference) UnmarshalBinary(data []byte) error {
+	meta, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if meta != core.CORE_ESCROW_REFERENCE {
+		return errors.New("error escrow reference data")
+	}
+	params := msg.(*pb.EscrowReference)
+
+	_, owner, err := as.NewAccountFromBytes(params.Owner)
+	if err != nil {
+		return err
+	}
+	p.Owner = owner
+	p.Sequence = params.Sequence
+	p.Operation = params.Operation
+	p.Preimage = libcore.Bytes(params.Preimage)
+	return nil
+}
+
+func (p *EscrowReference) MarshalBinary() ([]byte, error) {
+	owner, err := addressToByte(p.Owner)
+	if err != nil {
+		return nil, err
+	}
+	return core.Marshal(&pb.EscrowReference{
+		Owner:     owner,
+		Sequence:  p.Sequence,
+		Operation: p.Operation,
+		Preimage:  []byte(p.Preimage),
+	})
+}
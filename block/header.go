@@ -0,0 +1,176 @@
+package block
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/core/pb"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+// BlockHeader holds the fields of a block without its transactions and
+// states. Its hash is the hash of the whole block.
+type BlockHeader struct {
+	Hash libcore.Hash
+
+	BlockIndex      uint64
+	ParentHash      libcore.Hash
+	RootHash        libcore.Hash
+	TransactionHash libcore.Hash
+	StateHash       libcore.Hash
+	Timestamp       int64
+	BaseFee         int64
+}
+
+// NewBlockHeader copies the header fields of the block.
+func NewBlockHeader(b libblock.Block) *BlockHeader {
+	h := &BlockHeader{
+		Hash:            b.GetHash(),
+		BlockIndex:      b.GetIndex(),
+		ParentHash:      b.GetParentHash(),
+		RootHash:        b.GetRootHash(),
+		TransactionHash: b.GetTransactionHash(),
+		StateHash:       b.GetStateHash(),
+		Timestamp:       b.GetTime(),
+	}
+	if bb, ok := b.(interface{ GetBaseFee() int64 }); ok {
+		h.BaseFee = bb.GetBaseFee()
+	}
+	return h
+}
+
+func (h *BlockHeader) GetIndex() uint64 {
+	return h.BlockIndex
+}
+
+func (h *BlockHeader) GetHash() libcore.Hash {
+	return h.Hash
+}
+
+func (h *BlockHeader) SetHash(hash libcore.Hash) {
+	h.Hash = hash
+}
+
+func (h *BlockHeader) GetParentHash() libcore.Hash {
+	return h.ParentHash
+}
+
+func (h *BlockHeader) GetRootHash() libcore.Hash {
+	return h.RootHash
+}
+
+func (h *BlockHeader) GetTransactionHash() libcore.Hash {
+	return h.TransactionHash
+}
+
+func (h *BlockHeader) GetStateHash() libcore.Hash {
+	return h.StateHash
+}
+
+func (h *BlockHeader) GetTime() int64 {
+	return h.Timestamp
+}
+
+func (h *BlockHeader) GetBaseFee() int64 {
+	return h.BaseFee
+}
+
+func (h *BlockHeader) UnmarshalBinary(data []byte) error {
+	meta, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+
+	switch meta {
+	case core.CORE_BLOCK_HEADER:
+		header := msg.(*pb.BlockHeader)
+		h.BlockIndex = header.BlockIndex
+		h.ParentHash = libcore.Hash(header.ParentHash)
+		h.RootHash = libcore.Hash(header.RootHash)
+		h.TransactionHash = libcore.Hash(header.TransactionHash)
+		h.StateHash = libcore.Hash(header.StateHash)
+		h.Timestamp = header.Timestamp
+		h.BaseFee = header.BaseFee
+	case core.CORE_BLOCK:
+		block := msg.(*pb.Block)
+		h.BlockIndex = block.BlockIndex
+		h.ParentHash = libcore.Hash(block.ParentHash)
+		h.RootHash = libcore.Hash(block.RootHash)
+		h.TransactionHash = libcore.Hash(block.TransactionHash)
+		h.StateHash = libcore.Hash(block.StateHash)
+		h.Timestamp = block.Timestamp
+		h.BaseFee = block.BaseFee
+	default:
+		return errors.New("error block header data")
+	}
+	return nil
+}
+
+func (h *BlockHeader) MarshalBinary() ([]byte, error) {
+	return core.Marshal(&pb.BlockHeader{
+		BlockIndex:      h.BlockIndex,
+		ParentHash:      []byte(h.ParentHash),
+		RootHash:        []byte(h.RootHash),
+		TransactionHash: []byte(h.TransactionHash),
+		StateHash:       []byte(h.StateHash),
+		Timestamp:       h.Timestamp,
+		BaseFee:         h.BaseFee,
+	})
+}
+
+func (h *BlockHeader) Raw(ignoreSigningFields bool) ([]byte, error) {
+	return h.MarshalBinary()
+}
+
+// NewLazyBlock returns a block with the fields of the header, which reads
+// its transactions and states with load on first use. The loaded data is the
+// output of MarshalBody.
+func NewLazyBlock(h *BlockHeader, load func() ([]byte, error)) *Block {
+	return &Block{
+		Hash:            h.Hash,
+		BlockIndex:      h.BlockIndex,
+		ParentHash:      h.ParentHash,
+		RootHash:        h.RootHash,
+		TransactionHash: h.TransactionHash,
+		StateHash:       h.StateHash,
+		Timestamp:       h.Timestamp,
+		BaseFee:         h.BaseFee,
+
+		load:     load,
+		loadOnce: &sync.Once{},
+	}
+}
+
+// MarshalBody encodes the transactions and states of the block, which are
+// stored apart from its header.
+func MarshalBody(b libblock.Block) ([]byte, error) {
+	var block *pb.Block
+	if bb, ok := b.(*Block); ok {
+		var err error
+		block, err = bb.toProto(false, false)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		data, err := b.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		_, msg, err := core.Unmarshal(data)
+		if err != nil {
+			return nil, err
+		}
+		var ok bool
+		block, ok = msg.(*pb.Block)
+		if !ok {
+			return nil, errors.New("error block")
+		}
+	}
+	return core.Marshal(&pb.BlockBody{
+		Transactions: block.Transactions,
+		States:       block.States,
+	})
+}
@@ -15,6 +15,13 @@ type Receipt struct {
 	BlockIndex        uint64
 	TransactionIndex  uint32
 	TransactionResult libblock.TransactionResult
+	Fee               int64         // the fee charged to the account of the transaction
+	Preimage          libcore.Bytes // the preimage revealed to finish a hash-locked escrow
+
+	// Results are the results of the operations of a compound transaction,
+	// and Preimages the preimages they revealed, if it was applied.
+	Results   []libblock.TransactionResult
+	Preimages []libcore.Bytes
 
 	States []libblock.State
 }
@@ -36,10 +43,23 @@ func (r *Receipt) UnmarshalBinary(data []byte) error {
 	if meta != core.CORE_RECEIPT {
 		return errors.New("error receipt data")
 	}
-	receipt := msg.(*pb.Receipt)
+	return r.fromProto(msg.(*pb.Receipt))
+}
+
+func (r *Receipt) fromProto(receipt *pb.Receipt) error {
 	r.TransactionResult = libblock.TransactionResult(receipt.TransactionResult)
 	r.TransactionIndex = receipt.TransactionIndex
 	r.BlockIndex = receipt.BlockIndex
+	r.Fee = receipt.Fee
+	r.Preimage = libcore.Bytes(receipt.Preimage)
+	r.Results = nil
+	for _, result := range receipt.Results {
+		r.Results = append(r.Results, libblock.TransactionResult(result))
+	}
+	r.Preimages = nil
+	for _, preimage := range receipt.Preimages {
+		r.Preimages = append(r.Preimages, libcore.Bytes(preimage))
+	}
 
 	list := receipt.GetStates()
 	l := len(list)
@@ -57,7 +77,7 @@ func (r *Receipt) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
-func (r *Receipt) MarshalBinary() ([]byte, error) {
+func (r *Receipt) toProto() (*pb.Receipt, error) {
 	l := len(r.States)
 	states := make([][]byte, l)
 	for i := 0; i < l; i++ {
@@ -68,16 +88,35 @@ func (r *Receipt) MarshalBinary() ([]byte, error) {
 		}
 		states[i] = data
 	}
-	receipt := &pb.Receipt{
+	return &pb.Receipt{
 		TransactionResult: uint32(r.TransactionResult),
 		TransactionIndex:  r.TransactionIndex,
 		BlockIndex:        r.BlockIndex,
+		Fee:               r.Fee,
+		Preimage:          []byte(r.Preimage),
+		Results:           r.resultsToProto(),
+		Preimages:         r.preimagesToProto(),
 		States:            states,
+	}, nil
+}
+
+func (r *Receipt) resultsToProto() []uint32 {
+	var results []uint32
+	for _, result := range r.Results {
+		results = append(results, uint32(result))
 	}
-	return core.Marshal(receipt)
+	return results
 }
 
-func (r *Receipt) Raw(ignoreSigningFields bool) ([]byte, error) {
+func (r *Receipt) preimagesToProto() [][]byte {
+	var preimages [][]byte
+	for _, preimage := range r.Preimages {
+		preimages = append(preimages, []byte(preimage))
+	}
+	return preimages
+}
+
+func (r *Receipt) rawProto(ignoreSigningFields bool) (*pb.Receipt, error) {
 	l := len(r.States)
 	states := make([][]byte, l)
 	for i := 0; i < l; i++ {
@@ -88,13 +127,63 @@ func (r *Receipt) Raw(ignoreSigningFields bool) ([]byte, error) {
 		}
 		states[i] = data
 	}
-	receipt := &pb.Receipt{
+	return &pb.Receipt{
 		TransactionResult: uint32(r.TransactionResult),
+		Fee:               r.Fee,
+		Preimage:          []byte(r.Preimage),
+		Results:           r.resultsToProto(),
+		Preimages:         r.preimagesToProto(),
 		States:            states,
+	}, nil
+}
+
+func (r *Receipt) MarshalBinary() ([]byte, error) {
+	receipt, err := r.toProto()
+	if err != nil {
+		return nil, err
+	}
+	return core.Marshal(receipt)
+}
+
+func (r *Receipt) Raw(ignoreSigningFields bool) ([]byte, error) {
+	receipt, err := r.rawProto(ignoreSigningFields)
+	if err != nil {
+		return nil, err
 	}
 	return core.Marshal(receipt)
 }
 
+// receiptToProto converts without encoding for the receipts of this package,
+// and through their binary form for other implementations.
+func receiptToProto(r libblock.Receipt, raw bool, ignoreSigningFields bool) (*pb.Receipt, error) {
+	if receipt, ok := r.(*Receipt); ok {
+		if raw {
+			return receipt.rawProto(ignoreSigningFields)
+		}
+		return receipt.toProto()
+	}
+
+	var data []byte
+	var err error
+	if raw {
+		data, err = r.Raw(ignoreSigningFields)
+	} else {
+		data, err = r.MarshalBinary()
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	receipt, ok := msg.(*pb.Receipt)
+	if !ok {
+		return nil, errors.New("error receipt data")
+	}
+	return receipt, nil
+}
+
 func (r *Receipt) GetTransactionIndex() uint32 {
 	return r.TransactionIndex
 }
@@ -115,6 +204,22 @@ func (r *Receipt) GetTransactionResult() libblock.TransactionResult {
 	return r.TransactionResult
 }
 
+func (r *Receipt) GetFee() int64 {
+	return r.Fee
+}
+
+func (r *Receipt) GetPreimage() libcore.Bytes {
+	return r.Preimage
+}
+
+func (r *Receipt) GetResults() []libblock.TransactionResult {
+	return r.Results
+}
+
+func (r *Receipt) GetPreimages() []libcore.Bytes {
+	return r.Preimages
+}
+
 func (r *Receipt) GetStates() []libblock.State {
 	return r.States
 }
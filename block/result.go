@@ -13,8 +13,15 @@ const (
 	TrEXCEPTION libblock.TransactionResult = -3 // Unexpected program state.
 	TrALREADY   libblock.TransactionResult = -4 // Already in the ledger.
 
-	TrNO_ENTRY   libblock.TransactionResult = -101 // No matching entry found.
-	TrNO_ACCOUNT libblock.TransactionResult = -102 // The account does not exist.
+	TrNO_ENTRY      libblock.TransactionResult = -101 // No matching entry found.
+	TrNO_ACCOUNT    libblock.TransactionResult = -102 // The account does not exist.
+	TrNO_TRUST      libblock.TransactionResult = -103 // The destination has no trust line with room for the amount.
+	TrFROZEN        libblock.TransactionResult = -104 // The currency or the trust line is frozen.
+	TrNO_AUTH       libblock.TransactionResult = -105 // The trust line isn't authorized by the issuer.
+	TrNO_PERMISSION libblock.TransactionResult = -106 // The issuer doesn't allow the operation.
+	TrNO_KEY        libblock.TransactionResult = -107 // The account would have no key to sign with.
+	TrNOT_READY     libblock.TransactionResult = -108 // The time to finish or cancel hasn't come.
+	TrEXPIRED       libblock.TransactionResult = -109 // The time to finish has passed.
 
 	TrINSUFF_GAS libblock.TransactionResult = -110 // Insufficient balance to pay gas.
 
@@ -39,8 +46,15 @@ var resultNames = map[libblock.TransactionResult]struct {
 	TrEXCEPTION: {"trEXCEPTION", `Unexpected program state.`},
 	TrALREADY:   {"trALREADY", `Already in the ledger.`},
 
-	TrNO_ENTRY:   {"trNO_ENTRY", `No matching entry found.`},
-	TrNO_ACCOUNT: {"trNO_ACCOUNT", `The account does not exist.`},
+	TrNO_ENTRY:      {"trNO_ENTRY", `No matching entry found.`},
+	TrNO_ACCOUNT:    {"trNO_ACCOUNT", `The account does not exist.`},
+	TrNO_TRUST:      {"trNO_TRUST", `The destination has no trust line with room for the amount.`},
+	TrFROZEN:        {"trFROZEN", `The currency or the trust line is frozen.`},
+	TrNO_AUTH:       {"trNO_AUTH", `The trust line isn't authorized by the issuer.`},
+	TrNO_PERMISSION: {"trNO_PERMISSION", `The issuer doesn't allow the operation.`},
+	TrNO_KEY:        {"trNO_KEY", `The account would have no key to sign with.`},
+	TrNOT_READY:     {"trNOT_READY", `The time to finish or cancel hasn't come.`},
+	TrEXPIRED:       {"trEXPIRED", `The time to finish has passed.`},
 
 	TrINSUFF_GAS: {"trINSUFF_GAS", `Insufficient balance to pay gas.`},
 
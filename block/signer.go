@@ -0,0 +1,203 @@
+package block
+
+import (
+	"errors"
+
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/core/pb"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+// SignerEntry is an account allowed to sign for another one, with the weight
+// of its signature.
+type SignerEntry struct {
+	Account libcore.Address
+	Weight  uint32
+}
+
+func signerEntriesFromProto(list []*pb.SignerEntry) ([]SignerEntry, error) {
+	entries := make([]SignerEntry, len(list))
+	for i, e := range list {
+		_, account, err := as.NewAccountFromBytes(e.Account)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = SignerEntry{
+			Account: account,
+			Weight:  e.Weight,
+		}
+	}
+	return entries, nil
+}
+
+func signerEntriesToProto(entries []SignerEntry) ([]*pb.SignerEntry, error) {
+	list := make([]*pb.SignerEntry, len(entries))
+	for i, e := range entries {
+		account, err := e.Account.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		list[i] = &pb.SignerEntry{
+			Account: account,
+			Weight:  e.Weight,
+		}
+	}
+	return list, nil
+}
+
+// SignerListState lets the signers sign the transactions of its account
+// together, when the weights of their signatures reach the quorum.
+type SignerListState struct {
+	State
+
+	Quorum  uint32
+	Signers []SignerEntry
+}
+
+func (s *SignerListState) GetStateKey() string {
+	return s.Account.String()
+}
+
+// GetWeight returns the weight of the account in the list, or 0 if it isn't
+// a signer.
+func (s *SignerListState) GetWeight(account libcore.Address) uint32 {
+	for _, e := range s.Signers {
+		if libcore.Equals(e.Account, account) {
+			return e.Weight
+		}
+	}
+	return 0
+}
+
+func (s *SignerListState) UnmarshalBinary(data []byte) error {
+	meta, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if meta != core.CORE_SIGNER_LIST_STATE {
+		return errors.New("error state data")
+	}
+	state := msg.(*pb.SignerListState)
+
+	_, account, err := as.NewAccountFromBytes(state.Account[:])
+	if err != nil {
+		return err
+	}
+	signers, err := signerEntriesFromProto(state.Signers)
+	if err != nil {
+		return err
+	}
+
+	s.StateType = libblock.StateType(core.CORE_SIGNER_LIST_STATE)
+	s.BlockIndex = state.BlockIndex
+	s.Account = account
+	s.Sequence = state.Sequence
+	s.Quorum = state.Quorum
+	s.Signers = signers
+	return nil
+}
+
+func (s *SignerListState) toProto(ignoreVariableFields bool) (*pb.SignerListState, error) {
+	a, err := s.Account.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	signers, err := signerEntriesToProto(s.Signers)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &pb.SignerListState{
+		StateType: uint32(core.CORE_SIGNER_LIST_STATE),
+		Account:   a,
+		Sequence:  s.Sequence,
+		Quorum:    s.Quorum,
+		Signers:   signers,
+	}
+	if !ignoreVariableFields {
+		state.BlockIndex = s.BlockIndex
+	}
+	return state, nil
+}
+
+func (s *SignerListState) MarshalBinary() ([]byte, error) {
+	state, err := s.toProto(false)
+	if err != nil {
+		return nil, err
+	}
+	return core.Marshal(state)
+}
+
+func (s *SignerListState) Raw(ignoreSigningFields bool) ([]byte, error) {
+	state, err := s.toProto(true)
+	if err != nil {
+		return nil, err
+	}
+	return core.Marshal(state)
+}
+
+// SignerListSet is the payload of a SIGNER_LIST_SET transaction, which sets
+// the signer list of its account, or removes it with a quorum of 0 and no
+// signers.
+type SignerListSet struct {
+	Quorum  uint32
+	Signers []SignerEntry
+}
+
+func (p *SignerListSet) UnmarshalBinary(data []byte) error {
+	meta, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if meta != core.CORE_SIGNER_LIST_SET {
+		return errors.New("error signer list set data")
+	}
+	params := msg.(*pb.SignerListSet)
+
+	signers, err := signerEntriesFromProto(params.Signers)
+	if err != nil {
+		return err
+	}
+	p.Quorum = params.Quorum
+	p.Signers = signers
+	return nil
+}
+
+func (p *SignerListSet) MarshalBinary() ([]byte, error) {
+	signers, err := signerEntriesToProto(p.Signers)
+	if err != nil {
+		return nil, err
+	}
+	return core.Marshal(&pb.SignerListSet{
+		Quorum:  p.Quorum,
+		Signers: signers,
+	})
+}
+
+// SetRegularKey is the payload of a SET_REGULAR_KEY transaction, which sets
+// the regular key of its account, or removes it if PublicKey is empty.
+type SetRegularKey struct {
+	PublicKey libcore.PublicKey
+}
+
+func (p *SetRegularKey) UnmarshalBinary(data []byte) error {
+	meta, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if meta != core.CORE_SET_REGULAR_KEY {
+		return errors.New("error set regular key data")
+	}
+	params := msg.(*pb.SetRegularKey)
+
+	p.PublicKey = libcore.PublicKey(params.PublicKey)
+	return nil
+}
+
+func (p *SetRegularKey) MarshalBinary() ([]byte, error) {
+	return core.Marshal(&pb.SetRegularKey{
+		PublicKey: []byte(p.PublicKey),
+	})
+}
@@ -52,8 +52,20 @@ type AccountState struct {
 	State
 
 	Amount core.Amount
+
+	// RegularKey may sign for the account in place of its master key. It and
+	// Flags are kept in the state of the native currency.
+	RegularKey libcore.PublicKey
+	Flags      uint32 // ACCOUNT_* flags
 }
 
+// Flags of AccountState.
+const (
+	ACCOUNT_DISABLE_MASTER uint32 = 1 << iota // the master key can't sign for the account
+
+	ACCOUNT_FLAGS = ACCOUNT_DISABLE_MASTER
+)
+
 func (s *AccountState) GetStateKey() string {
 	return core.GetAccountKey(s.Account, s.Amount.Currency, s.Amount.Issuer, "-")
 }
@@ -84,6 +96,8 @@ func (s *AccountState) UnmarshalBinary(data []byte) error {
 	s.Account = account
 	s.Sequence = state.Sequence
 	s.Amount = *a
+	s.RegularKey = libcore.PublicKey(state.RegularKey)
+	s.Flags = state.Flags
 	return nil
 }
 
@@ -99,6 +113,8 @@ func (s *AccountState) MarshalBinary() ([]byte, error) {
 		Account:    a,
 		Sequence:   s.Sequence,
 		Amount:     s.Amount.String(),
+		RegularKey: []byte(s.RegularKey),
+		Flags:      s.Flags,
 	})
 }
 
@@ -109,10 +125,12 @@ func (s *AccountState) Raw(ignoreSigningFields bool) ([]byte, error) {
 	}
 
 	return core.Marshal(&pb.AccountState{
-		StateType: uint32(core.CORE_ACCOUNT_STATE),
-		Account:   a,
-		Sequence:  s.Sequence,
-		Amount:    s.Amount.String(),
+		StateType:  uint32(core.CORE_ACCOUNT_STATE),
+		Account:    a,
+		Sequence:   s.Sequence,
+		Amount:     s.Amount.String(),
+		RegularKey: []byte(s.RegularKey),
+		Flags:      s.Flags,
 	})
 }
 
@@ -123,8 +141,19 @@ type CurrencyState struct {
 	Symbol      string
 	Decimals    uint32
 	TotalSupply core.Amount
+	Flags       uint32 // CURRENCY_* flags
+	TrustLines  uint64 // number of trust lines to the currency
 }
 
+// Flags of CurrencyState, set by the issuer.
+const (
+	CURRENCY_REQUIRE_AUTH   uint32 = 1 << iota // holders need a trust line authorized by the issuer
+	CURRENCY_FROZEN                            // balances move only from or to the issuer
+	CURRENCY_ALLOW_CLAWBACK                    // the issuer may take balances back; set before any trust line, can't be cleared
+
+	CURRENCY_FLAGS = CURRENCY_REQUIRE_AUTH | CURRENCY_FROZEN | CURRENCY_ALLOW_CLAWBACK
+)
+
 func (s *CurrencyState) GetStateKey() string {
 	return core.GetCurrencyKey(s.TotalSupply.Currency, s.TotalSupply.Issuer, "-")
 }
@@ -157,6 +186,8 @@ func (s *CurrencyState) UnmarshalBinary(data []byte) error {
 	s.Symbol = state.Symbol
 	s.Decimals = state.Decimals
 	s.TotalSupply = *a
+	s.Flags = state.Flags
+	s.TrustLines = state.TrustLines
 
 	return nil
 }
@@ -176,6 +207,8 @@ func (s *CurrencyState) MarshalBinary() ([]byte, error) {
 		Symbol:      s.Symbol,
 		Decimals:    s.Decimals,
 		TotalSupply: s.TotalSupply.String(),
+		Flags:       s.Flags,
+		TrustLines:  s.TrustLines,
 	})
 }
 
@@ -193,6 +226,8 @@ func (s *CurrencyState) Raw(ignoreSigningFields bool) ([]byte, error) {
 		Symbol:      s.Symbol,
 		Decimals:    s.Decimals,
 		TotalSupply: s.TotalSupply.String(),
+		Flags:       s.Flags,
+		TrustLines:  s.TrustLines,
 	})
 }
 
@@ -216,6 +251,27 @@ func ReadState(data []byte) (libblock.State, error) {
 			return nil, err
 		}
 		return s, nil
+	case core.CORE_TRUST_LINE_STATE:
+		s := &TrustLineState{}
+		err := s.UnmarshalBinary(data)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case core.CORE_SIGNER_LIST_STATE:
+		s := &SignerListState{}
+		err := s.UnmarshalBinary(data)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case core.CORE_ESCROW_STATE:
+		s := &EscrowState{}
+		err := s.UnmarshalBinary(data)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
 	default:
 		return nil, errors.New("error data")
 	}
@@ -232,3 +288,29 @@ func CloneState(state libblock.State) (libblock.State, error) {
 	}
 	return s, nil
 }
+
+// CopyState returns a copy of the state without encoding it. The copy shares
+// the hashes, addresses, keys and symbols of the state, which are never
+// changed in place.
+func CopyState(state libblock.State) libblock.State {
+	switch s := state.(type) {
+	case *AccountState:
+		c := *s
+		return &c
+	case *CurrencyState:
+		c := *s
+		return &c
+	case *TrustLineState:
+		c := *s
+		return &c
+	case *SignerListState:
+		c := *s
+		c.Signers = append([]SignerEntry(nil), s.Signers...)
+		return &c
+	case *EscrowState:
+		c := *s
+		return &c
+	default:
+		return state
+	}
+}
@@ -26,6 +26,18 @@ type Transaction struct {
 	Payload     libcore.Bytes
 	PublicKey   libcore.PublicKey
 	Signature   libcore.Signature
+
+	// Signers sign a multi-signed transaction in place of PublicKey and
+	// Signature, with the weights of the signer list of Account.
+	Signers []Signer
+}
+
+// Signer is a signature of a multi-signed transaction, by the master or the
+// regular key of Account.
+type Signer struct {
+	Account   libcore.Address
+	PublicKey libcore.PublicKey
+	Signature libcore.Signature
 }
 
 func (tx *Transaction) GetIndex() uint64 {
@@ -41,6 +53,9 @@ func (tx *Transaction) SetHash(h libcore.Hash) {
 }
 
 func byteToAddress(b []byte) (libcore.Address, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
 	_, a, err := as.NewAccountFromBytes(b)
 	if err != nil {
 		fmt.Println("bytes", len(b), hex.EncodeToString(b))
@@ -50,8 +65,6 @@ func byteToAddress(b []byte) (libcore.Address, error) {
 }
 
 func (tx *Transaction) UnmarshalBinary(data []byte) error {
-	var err error
-
 	meta, msg, err := core.Unmarshal(data)
 	if err != nil {
 		return err
@@ -59,7 +72,11 @@ func (tx *Transaction) UnmarshalBinary(data []byte) error {
 	if meta != core.CORE_TRANSACTION {
 		return errors.New("error transaction data")
 	}
-	t := msg.(*pb.Transaction)
+	return tx.fromProto(msg.(*pb.Transaction))
+}
+
+func (tx *Transaction) fromProto(t *pb.Transaction) error {
+	var err error
 
 	tx.TransactionType = libblock.TransactionType(t.TransactionType)
 
@@ -86,14 +103,33 @@ func (tx *Transaction) UnmarshalBinary(data []byte) error {
 	tx.PublicKey = libcore.PublicKey(t.PublicKey)
 	tx.Signature = libcore.Signature(t.Signature)
 
+	tx.Signers = nil
+	if len(t.Signers) > 0 {
+		tx.Signers = make([]Signer, len(t.Signers))
+		for i, s := range t.Signers {
+			account, err := byteToAddress(s.Account)
+			if err != nil {
+				return err
+			}
+			tx.Signers[i] = Signer{
+				Account:   account,
+				PublicKey: libcore.PublicKey(s.PublicKey),
+				Signature: libcore.Signature(s.Signature),
+			}
+		}
+	}
+
 	return nil
 }
 
 func addressToByte(a libcore.Address) ([]byte, error) {
+	if a == nil {
+		return nil, nil
+	}
 	return a.MarshalBinary()
 }
 
-func (tx *Transaction) MarshalBinary() ([]byte, error) {
+func (tx *Transaction) toProto(ignoreSigningFields bool) (*pb.Transaction, error) {
 	fromData, err := addressToByte(tx.Account)
 	if err != nil {
 		return nil, err
@@ -113,35 +149,40 @@ func (tx *Transaction) MarshalBinary() ([]byte, error) {
 		Destination: toData,
 		Payload:     tx.Payload,
 		PublicKey:   []byte(tx.PublicKey),
-		Signature:   []byte(tx.Signature),
+	}
+	if !ignoreSigningFields {
+		t.Signature = []byte(tx.Signature)
+
+		// every signer signs the transaction without the others
+		for _, s := range tx.Signers {
+			account, err := addressToByte(s.Account)
+			if err != nil {
+				return nil, err
+			}
+			t.Signers = append(t.Signers, &pb.Signer{
+				Account:   account,
+				PublicKey: []byte(s.PublicKey),
+				Signature: []byte(s.Signature),
+			})
+		}
+	}
+	return t, nil
+}
+
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	t, err := tx.toProto(false)
+	if err != nil {
+		return nil, err
 	}
 	return core.Marshal(t)
 }
 
 func (tx *Transaction) Raw(ignoreSigningFields bool) ([]byte, error) {
-	if ignoreSigningFields {
-		fromAccount, err := addressToByte(tx.Account)
-		if err != nil {
-			return nil, err
-		}
-		toAccount, err := addressToByte(tx.Destination)
-		if err != nil {
-			return nil, err
-		}
-		t := &pb.Transaction{
-			TransactionType: uint32(tx.TransactionType),
-
-			Account:     fromAccount,
-			Sequence:    tx.Sequence,
-			Amount:      tx.Amount.String(),
-			Gas:         tx.Gas,
-			Destination: toAccount,
-			Payload:     tx.Payload,
-			PublicKey:   []byte(tx.PublicKey),
-		}
-		return core.Marshal(t)
+	t, err := tx.toProto(ignoreSigningFields)
+	if err != nil {
+		return nil, err
 	}
-	return tx.MarshalBinary()
+	return core.Marshal(t)
 }
 
 func (tx *Transaction) GetTransactionType() libblock.TransactionType {
@@ -191,29 +232,33 @@ func (txWithData *TransactionWithData) GetReceipt() libblock.Receipt {
 
 func (txWithData *TransactionWithData) UnmarshalBinary(data []byte) error {
 	meta, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return err
+	}
 	if meta != core.CORE_TRANSACTION_WITH_DATA {
 		return errors.New("error transaction with data")
 	}
+	return txWithData.fromProto(msg.(*pb.TransactionWithData))
+}
 
-	td := msg.(*pb.TransactionWithData)
-
-	txData, err := core.Marshal(td.Transaction)
-	if err != nil {
-		return err
+func (txWithData *TransactionWithData) fromProto(td *pb.TransactionWithData) error {
+	if td.Transaction == nil {
+		return errors.New("error transaction with data")
 	}
+
 	tx := &Transaction{}
-	err = tx.UnmarshalBinary(txData)
+	err := tx.fromProto(td.Transaction)
 	if err != nil {
 		return err
 	}
 
-	receiptData, err := core.Marshal(td.Receipt)
-	if err != nil {
-		log.Println(err)
-		return err
+	// a missing receipt decodes as an empty one
+	r := td.Receipt
+	if r == nil {
+		r = &pb.Receipt{}
 	}
 	receipt := &Receipt{}
-	err = receipt.UnmarshalBinary(receiptData)
+	err = receipt.fromProto(r)
 	if err != nil {
 		log.Println(err)
 		return err
@@ -225,70 +270,65 @@ func (txWithData *TransactionWithData) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
-func (txWithData *TransactionWithData) MarshalBinary() ([]byte, error) {
-	txData, err := txWithData.Transaction.MarshalBinary()
+// toProto converts to the message of MarshalBinary, or of Raw if raw is set.
+func (txWithData *TransactionWithData) toProto(raw bool, ignoreSigningFields bool) (*pb.TransactionWithData, error) {
+	tx, err := transactionToProto(txWithData.Transaction, raw, ignoreSigningFields)
 	if err != nil {
 		return nil, err
 	}
-	_, msg, err := core.Unmarshal(txData)
-	if err != nil {
-		return nil, err
-	}
-	tx := msg.(*pb.Transaction)
-
-	receiptData, err := txWithData.Receipt.MarshalBinary()
+	receipt, err := receiptToProto(txWithData.Receipt, raw, ignoreSigningFields)
 	if err != nil {
 		return nil, err
 	}
-	_, msg, err = core.Unmarshal(receiptData)
-	if err != nil {
-		return nil, err
-	}
-	receipt := msg.(*pb.Receipt)
-
-	td := &pb.TransactionWithData{
+	return &pb.TransactionWithData{
 		Transaction: tx,
 		Receipt:     receipt,
 		Date:        txWithData.Date,
-	}
-	data, err := core.Marshal(td)
+	}, nil
+}
+
+func (txWithData *TransactionWithData) MarshalBinary() ([]byte, error) {
+	td, err := txWithData.toProto(false, false)
 	if err != nil {
 		return nil, err
 	}
-	return data, nil
+	return core.Marshal(td)
 }
 
 func (txWithData *TransactionWithData) Raw(ignoreSigningFields bool) ([]byte, error) {
-	txData, err := txWithData.Transaction.Raw(ignoreSigningFields)
+	td, err := txWithData.toProto(true, ignoreSigningFields)
 	if err != nil {
 		return nil, err
 	}
-	_, msg, err := core.Unmarshal(txData)
-	if err != nil {
-		return nil, err
+	return core.Marshal(td)
+}
+
+// transactionToProto converts without encoding for the transactions of this
+// package, and through their binary form for other implementations.
+func transactionToProto(tx libblock.Transaction, raw bool, ignoreSigningFields bool) (*pb.Transaction, error) {
+	if t, ok := tx.(*Transaction); ok {
+		return t.toProto(raw && ignoreSigningFields)
 	}
-	tx := msg.(*pb.Transaction)
 
-	receiptData, err := txWithData.Receipt.Raw(ignoreSigningFields)
-	if err != nil {
-		return nil, err
+	var data []byte
+	var err error
+	if raw {
+		data, err = tx.Raw(ignoreSigningFields)
+	} else {
+		data, err = tx.MarshalBinary()
 	}
-	_, msg, err = core.Unmarshal(receiptData)
 	if err != nil {
 		return nil, err
 	}
-	receipt := msg.(*pb.Receipt)
-
-	td := &pb.TransactionWithData{
-		Transaction: tx,
-		Receipt:     receipt,
-		Date:        txWithData.Date,
-	}
-	data, err := core.Marshal(td)
+	_, msg, err := core.Unmarshal(data)
 	if err != nil {
 		return nil, err
 	}
-	return data, nil
+	t, ok := msg.(*pb.Transaction)
+	if !ok {
+		return nil, errors.New("error transaction data")
+	}
+	return t, nil
 }
 
 func ReadTransaction(data []byte) (libblock.Transaction, error) {
@@ -0,0 +1,91 @@
+package block
+
+import (
+	"errors"
+
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/core/pb"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+)
+
+// TrustLineState lets its account hold a currency of an issuer, up to the
+// value of Limit. The currency and issuer are the ones of Limit.
+type TrustLineState struct {
+	State
+
+	Limit core.Amount
+	Flags uint32 // TRUST_LINE_* flags
+}
+
+// Flags of TrustLineState, set by the issuer.
+const (
+	TRUST_LINE_AUTHORIZED uint32 = 1 << iota // the issuer allows the account to hold the currency
+	TRUST_LINE_FROZEN                        // the balance moves only from or to the issuer
+
+	TRUST_LINE_FLAGS = TRUST_LINE_AUTHORIZED | TRUST_LINE_FROZEN
+)
+
+func (s *TrustLineState) GetStateKey() string {
+	return core.GetAccountKey(s.Account, s.Limit.Currency, s.Limit.Issuer, "-")
+}
+
+func (s *TrustLineState) UnmarshalBinary(data []byte) error {
+	meta, msg, err := core.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if meta != core.CORE_TRUST_LINE_STATE {
+		return errors.New("error state data")
+	}
+	state := msg.(*pb.TrustLineState)
+
+	_, account, err := as.NewAccountFromBytes(state.Account[:])
+	if err != nil {
+		return err
+	}
+
+	a, err := core.NewAmount(state.Limit)
+	if err != nil {
+		return err
+	}
+
+	s.StateType = libblock.StateType(core.CORE_TRUST_LINE_STATE)
+	s.BlockIndex = state.BlockIndex
+	s.Account = account
+	s.Sequence = state.Sequence
+	s.Limit = *a
+	s.Flags = state.Flags
+	return nil
+}
+
+func (s *TrustLineState) MarshalBinary() ([]byte, error) {
+	a, err := s.Account.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return core.Marshal(&pb.TrustLineState{
+		StateType:  uint32(core.CORE_TRUST_LINE_STATE),
+		BlockIndex: s.BlockIndex,
+		Account:    a,
+		Sequence:   s.Sequence,
+		Limit:      s.Limit.String(),
+		Flags:      s.Flags,
+	})
+}
+
+func (s *TrustLineState) Raw(ignoreSigningFields bool) ([]byte, error) {
+	a, err := s.Account.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return core.Marshal(&pb.TrustLineState{
+		StateType: uint32(core.CORE_TRUST_LINE_STATE),
+		Account:   a,
+		Sequence:  s.Sequence,
+		Limit:     s.Limit.String(),
+		Flags:     s.Flags,
+	})
+}
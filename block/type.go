@@ -11,22 +11,49 @@ var as = &account.AccountService{}
 
 // TransactionType
 const (
-	TRANSACTION libblock.TransactionType = libblock.TransactionType(core.CORE_TRANSACTION)
+	TRANSACTION     libblock.TransactionType = libblock.TransactionType(core.CORE_TRANSACTION)
+	ISSUE_CURRENCY  libblock.TransactionType = libblock.TransactionType(core.CORE_ISSUE_CURRENCY)
+	MINT            libblock.TransactionType = libblock.TransactionType(core.CORE_MINT)
+	BURN            libblock.TransactionType = libblock.TransactionType(core.CORE_BURN)
+	TRUST_SET       libblock.TransactionType = libblock.TransactionType(core.CORE_TRUST_SET)
+	CURRENCY_SET    libblock.TransactionType = libblock.TransactionType(core.CORE_CURRENCY_SET)
+	TRUST_CONTROL   libblock.TransactionType = libblock.TransactionType(core.CORE_TRUST_CONTROL)
+	CLAWBACK        libblock.TransactionType = libblock.TransactionType(core.CORE_CLAWBACK)
+	SIGNER_LIST_SET libblock.TransactionType = libblock.TransactionType(core.CORE_SIGNER_LIST_SET)
+	SET_REGULAR_KEY libblock.TransactionType = libblock.TransactionType(core.CORE_SET_REGULAR_KEY)
+	ACCOUNT_SET     libblock.TransactionType = libblock.TransactionType(core.CORE_ACCOUNT_SET)
+	ESCROW_CREATE   libblock.TransactionType = libblock.TransactionType(core.CORE_ESCROW_CREATE)
+	ESCROW_FINISH   libblock.TransactionType = libblock.TransactionType(core.CORE_ESCROW_FINISH)
+	ESCROW_CANCEL   libblock.TransactionType = libblock.TransactionType(core.CORE_ESCROW_CANCEL)
+	COMPOUND        libblock.TransactionType = libblock.TransactionType(core.CORE_COMPOUND)
 )
 
 // StateType
 const (
-	ACCOUNT_STATE  libblock.StateType = libblock.StateType(core.CORE_ACCOUNT_STATE)
-	CURRENCY_STATE libblock.StateType = libblock.StateType(core.CORE_CURRENCY_STATE)
+	ACCOUNT_STATE     libblock.StateType = libblock.StateType(core.CORE_ACCOUNT_STATE)
+	CURRENCY_STATE    libblock.StateType = libblock.StateType(core.CORE_CURRENCY_STATE)
+	TRUST_LINE_STATE  libblock.StateType = libblock.StateType(core.CORE_TRUST_LINE_STATE)
+	SIGNER_LIST_STATE libblock.StateType = libblock.StateType(core.CORE_SIGNER_LIST_STATE)
+	ESCROW_STATE      libblock.StateType = libblock.StateType(core.CORE_ESCROW_STATE)
 )
 
 func init() {
 	// TransactionType
-	TRANSACTION.Register("Transaction", func(t libblock.TransactionType) libblock.Transaction {
-		tx := &Transaction{}
-		tx.TransactionType = t
-		return tx
-	})
+	TRANSACTION.Register("Transaction", newTransaction)
+	ISSUE_CURRENCY.Register("IssueCurrency", newTransaction)
+	MINT.Register("Mint", newTransaction)
+	BURN.Register("Burn", newTransaction)
+	TRUST_SET.Register("TrustSet", newTransaction)
+	CURRENCY_SET.Register("CurrencySet", newTransaction)
+	TRUST_CONTROL.Register("TrustControl", newTransaction)
+	CLAWBACK.Register("Clawback", newTransaction)
+	SIGNER_LIST_SET.Register("SignerListSet", newTransaction)
+	SET_REGULAR_KEY.Register("SetRegularKey", newTransaction)
+	ACCOUNT_SET.Register("AccountSet", newTransaction)
+	ESCROW_CREATE.Register("EscrowCreate", newTransaction)
+	ESCROW_FINISH.Register("EscrowFinish", newTransaction)
+	ESCROW_CANCEL.Register("EscrowCancel", newTransaction)
+	COMPOUND.Register("Compound", newTransaction)
 
 	// StateType
 	ACCOUNT_STATE.Register("AccountState", func(t libblock.StateType) libblock.State {
@@ -39,4 +66,25 @@ func init() {
 		info.StateType = t
 		return info
 	})
+	TRUST_LINE_STATE.Register("TrustLineState", func(t libblock.StateType) libblock.State {
+		info := &TrustLineState{}
+		info.StateType = t
+		return info
+	})
+	SIGNER_LIST_STATE.Register("SignerListState", func(t libblock.StateType) libblock.State {
+		info := &SignerListState{}
+		info.StateType = t
+		return info
+	})
+	ESCROW_STATE.Register("EscrowState", func(t libblock.StateType) libblock.State {
+		info := &EscrowState{}
+		info.StateType = t
+		return info
+	})
+}
+
+func newTransaction(t libblock.TransactionType) libblock.Transaction {
+	tx := &Transaction{}
+	tx.TransactionType = t
+	return tx
 }
@@ -0,0 +1,74 @@
+// Command migrate copies a chain from one store backend to another and checks
+// that the target has the same roots and head. An interrupted migration
+// resumes when the command is run again with the same target.
+//
+//	migrate -from ./data -to ./data2 -to-backend namespaced
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/crypto"
+	"github.com/tokentransfer/chain/node"
+	"github.com/tokentransfer/chain/store"
+
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+// config selects the data directory, backend and encryption keys of one side
+// of the migration.
+type config struct {
+	dir         string
+	backend     string
+	keys        string
+	keyFile     string
+	encryptKeys bool
+}
+
+var _ libcore.Config = (*config)(nil)
+
+func (c *config) GetDataDir() string           { return c.dir }
+func (c *config) GetSystemCode() string        { return core.SYSTEM_CODE }
+func (c *config) GetStoreBackend() string      { return c.backend }
+func (c *config) GetEncryptionKeys() string    { return c.keys }
+func (c *config) GetEncryptionKeyFile() string { return c.keyFile }
+func (c *config) GetEncryptKeys() bool         { return c.encryptKeys }
+
+func flags(c *config, side string) {
+	flag.StringVar(&c.dir, side, "", "data directory of the "+side+" chain")
+	flag.StringVar(&c.backend, side+"-backend", store.DEFAULT_BACKEND, "store backend of the "+side+" chain")
+	flag.StringVar(&c.keys, side+"-keys", "", "encryption keys of the "+side+" chain, as id:hex entries")
+	flag.StringVar(&c.keyFile, side+"-key-file", "", "file with the encryption keys of the "+side+" chain")
+	flag.BoolVar(&c.encryptKeys, side+"-encrypt-keys", false, "whether the "+side+" chain encrypts the keys too")
+}
+
+func main() {
+	from := &config{}
+	to := &config{}
+	flags(from, "from")
+	flags(to, "to")
+	flag.Parse()
+
+	if len(from.dir) == 0 || len(to.dir) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	progress, err := node.Migrate(from, to, &crypto.CryptoService{})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		os.Exit(1)
+	}
+	names := make([]string, 0, len(progress.Stores))
+	for name := range progress.Stores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%s: %d entries\n", name, progress.Stores[name].Entries)
+	}
+}
@@ -15,10 +15,32 @@ const (
 	CORE_RECEIPT               = byte(102)
 	CORE_TRANSACTION_WITH_DATA = byte(103)
 	CORE_MESSAGE               = byte(104)
+	CORE_BLOCK_HEADER          = byte(105)
+	CORE_BLOCK_BODY            = byte(106)
 
 	// CORE_STATE         = byte(110)
-	CORE_ACCOUNT_STATE  = byte(111)
-	CORE_CURRENCY_STATE = byte(112)
+	CORE_ACCOUNT_STATE     = byte(111)
+	CORE_CURRENCY_STATE    = byte(112)
+	CORE_TRUST_LINE_STATE  = byte(113)
+	CORE_SIGNER_LIST_STATE = byte(114)
+	CORE_ESCROW_STATE      = byte(115)
+
+	CORE_ISSUE_CURRENCY   = byte(120)
+	CORE_MINT             = byte(121)
+	CORE_BURN             = byte(122)
+	CORE_TRUST_SET        = byte(123)
+	CORE_CURRENCY_SET     = byte(124)
+	CORE_TRUST_CONTROL    = byte(125)
+	CORE_CLAWBACK         = byte(126)
+	CORE_FLAGS_CHANGE     = byte(127)
+	CORE_SIGNER_LIST_SET  = byte(128)
+	CORE_SET_REGULAR_KEY  = byte(129)
+	CORE_ACCOUNT_SET      = byte(130)
+	CORE_ESCROW_CREATE    = byte(131)
+	CORE_ESCROW_FINISH    = byte(132)
+	CORE_ESCROW_CANCEL    = byte(133)
+	CORE_ESCROW_REFERENCE = byte(134)
+	CORE_COMPOUND         = byte(135)
 )
 
 var SYSTEM_CODE = "TEST"
@@ -37,11 +59,54 @@ func GetInfo(data []byte) string {
 			return "transaction_with_data"
 		case CORE_MESSAGE:
 			return "message"
+		case CORE_BLOCK_HEADER:
+			return "block_header"
+		case CORE_BLOCK_BODY:
+			return "block_body"
 
 		case CORE_ACCOUNT_STATE:
 			return "account_state"
 		case CORE_CURRENCY_STATE:
 			return "currency_state"
+		case CORE_TRUST_LINE_STATE:
+			return "trust_line_state"
+		case CORE_SIGNER_LIST_STATE:
+			return "signer_list_state"
+		case CORE_ESCROW_STATE:
+			return "escrow_state"
+
+		case CORE_ISSUE_CURRENCY:
+			return "issue_currency"
+		case CORE_MINT:
+			return "mint"
+		case CORE_BURN:
+			return "burn"
+		case CORE_TRUST_SET:
+			return "trust_set"
+		case CORE_CURRENCY_SET:
+			return "currency_set"
+		case CORE_TRUST_CONTROL:
+			return "trust_control"
+		case CORE_CLAWBACK:
+			return "clawback"
+		case CORE_FLAGS_CHANGE:
+			return "flags_change"
+		case CORE_SIGNER_LIST_SET:
+			return "signer_list_set"
+		case CORE_SET_REGULAR_KEY:
+			return "set_regular_key"
+		case CORE_ACCOUNT_SET:
+			return "account_set"
+		case CORE_ESCROW_CREATE:
+			return "escrow_create"
+		case CORE_ESCROW_FINISH:
+			return "escrow_finish"
+		case CORE_ESCROW_CANCEL:
+			return "escrow_cancel"
+		case CORE_ESCROW_REFERENCE:
+			return "escrow_reference"
+		case CORE_COMPOUND:
+			return "compound"
 		default:
 			return "unknown"
 		}
@@ -74,11 +139,36 @@ func Marshal(message proto.Message) ([]byte, error) {
 		meta = CORE_TRANSACTION_WITH_DATA
 	case *pb.MessageKey:
 		meta = CORE_MESSAGE
+	case *pb.BlockHeader:
+		meta = CORE_BLOCK_HEADER
+	case *pb.BlockBody:
+		meta = CORE_BLOCK_BODY
 
 	case *pb.AccountState:
 		meta = CORE_ACCOUNT_STATE
 	case *pb.CurrencyState:
 		meta = CORE_CURRENCY_STATE
+	case *pb.TrustLineState:
+		meta = CORE_TRUST_LINE_STATE
+	case *pb.SignerListState:
+		meta = CORE_SIGNER_LIST_STATE
+	case *pb.EscrowState:
+		meta = CORE_ESCROW_STATE
+
+	case *pb.IssueCurrency:
+		meta = CORE_ISSUE_CURRENCY
+	case *pb.FlagsChange:
+		meta = CORE_FLAGS_CHANGE
+	case *pb.SignerListSet:
+		meta = CORE_SIGNER_LIST_SET
+	case *pb.SetRegularKey:
+		meta = CORE_SET_REGULAR_KEY
+	case *pb.EscrowCreate:
+		meta = CORE_ESCROW_CREATE
+	case *pb.EscrowReference:
+		meta = CORE_ESCROW_REFERENCE
+	case *pb.Compound:
+		meta = CORE_COMPOUND
 
 	default:
 		err := errors.New("error data type")
@@ -115,11 +205,36 @@ func Unmarshal(data []byte) (byte, proto.Message, error) {
 			msg = &pb.TransactionWithData{}
 		case CORE_MESSAGE:
 			msg = &pb.MessageKey{}
+		case CORE_BLOCK_HEADER:
+			msg = &pb.BlockHeader{}
+		case CORE_BLOCK_BODY:
+			msg = &pb.BlockBody{}
 
 		case CORE_ACCOUNT_STATE:
 			msg = &pb.AccountState{}
 		case CORE_CURRENCY_STATE:
 			msg = &pb.CurrencyState{}
+		case CORE_TRUST_LINE_STATE:
+			msg = &pb.TrustLineState{}
+		case CORE_SIGNER_LIST_STATE:
+			msg = &pb.SignerListState{}
+		case CORE_ESCROW_STATE:
+			msg = &pb.EscrowState{}
+
+		case CORE_ISSUE_CURRENCY:
+			msg = &pb.IssueCurrency{}
+		case CORE_FLAGS_CHANGE:
+			msg = &pb.FlagsChange{}
+		case CORE_SIGNER_LIST_SET:
+			msg = &pb.SignerListSet{}
+		case CORE_SET_REGULAR_KEY:
+			msg = &pb.SetRegularKey{}
+		case CORE_ESCROW_CREATE:
+			msg = &pb.EscrowCreate{}
+		case CORE_ESCROW_REFERENCE:
+			msg = &pb.EscrowReference{}
+		case CORE_COMPOUND:
+			msg = &pb.Compound{}
 
 		default:
 			err := errors.New("error data format")
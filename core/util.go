@@ -31,6 +31,15 @@ func Init(c libcore.Config) {
 	config = c
 }
 
+// GetSystemCode returns the code of the native currency, SYSTEM_CODE until
+// Init is called with a config.
+func GetSystemCode() string {
+	if config == nil {
+		return SYSTEM_CODE
+	}
+	return config.GetSystemCode()
+}
+
 // Represents a time as the number of seconds since the epoch: January 1st, 2000 (00:00 UTC)
 type Time struct {
 	T uint32
@@ -224,13 +233,13 @@ func equalsAmountCurrency(a *Amount, b *Amount) bool {
 	if a.Currency != nil {
 		aa = a.Currency.String()
 	} else {
-		aa = config.GetSystemCode()
+		aa = GetSystemCode()
 	}
 	bb := ""
 	if b.Currency != nil {
 		bb = b.Currency.String()
 	} else {
-		bb = config.GetSystemCode()
+		bb = GetSystemCode()
 	}
 	return aa == bb
 }
@@ -302,7 +311,7 @@ func (a *Amount) IsNative() bool {
 		if a.Currency == nil {
 			return true
 		}
-		if a.Currency.String() == config.GetSystemCode() {
+		if a.Currency.String() == GetSystemCode() {
 			return true
 		}
 	}
@@ -316,7 +325,7 @@ func (a *Amount) String() string {
 	if a.Currency != nil && len(a.Currency.String()) > 0 {
 		list = append(list, a.Currency.String())
 	} else {
-		list = append(list, config.GetSystemCode())
+		list = append(list, GetSystemCode())
 	}
 	if a.Issuer != nil {
 		list = append(list, a.Issuer.String())
@@ -448,7 +457,7 @@ func GetAccountKey(account libcore.Address, currency *libcore.Symbol, issuer lib
 	if currency != nil && len(currency.String()) > 0 {
 		list = append(list, currency.String())
 	} else {
-		list = append(list, config.GetSystemCode())
+		list = append(list, GetSystemCode())
 	}
 	if issuer != nil {
 		list = append(list, issuer.String())
@@ -461,7 +470,7 @@ func GetCurrencyKey(currency *libcore.Symbol, issuer libcore.Address, seperator
 	if currency != nil && len(currency.String()) > 0 {
 		list = append(list, currency.String())
 	} else {
-		list = append(list, config.GetSystemCode())
+		list = append(list, GetSystemCode())
 	}
 	if issuer != nil {
 		list = append(list, issuer.String())
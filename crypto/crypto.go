@@ -1,6 +1,7 @@
 package crypto
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"errors"
 	"fmt"
@@ -115,3 +116,84 @@ func (service *CryptoService) Verify(s libcrypto.Signable) (bool, error) {
 	signature := s.GetSignature()
 	return p.Verify(hash, data, signature)
 }
+
+// AccountVerifier checks signatures against the keys allowed to sign for the
+// account of the signed data: its master key, unless it is disabled, and its
+// regular key.
+type AccountVerifier interface {
+	VerifyAccount(s libcrypto.Signable, regularKey libcore.PublicKey, masterDisabled bool) (bool, error)
+}
+
+// VerifyAccount is Verify, also accepting a signature by the regular key of
+// the account, and refusing the master key if it is disabled.
+func (service *CryptoService) VerifyAccount(s libcrypto.Signable, regularKey libcore.PublicKey, masterDisabled bool) (bool, error) {
+	publicKey := s.GetPublicKey()
+	if len(regularKey) > 0 && bytes.Equal(publicKey, regularKey) {
+		data, err := s.Raw(true)
+		if err != nil {
+			return false, err
+		}
+		_, err = service.VerifySignature(data, publicKey, s.GetSignature())
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if masterDisabled {
+		return false, errors.New("error signature")
+	}
+	return service.Verify(s)
+}
+
+// SignatureVerifier checks signatures which aren't bound to the account of
+// the signed data, as the signatures of multi-signed transactions.
+type SignatureVerifier interface {
+	VerifySignature(data []byte, publicKey libcore.PublicKey, signature libcore.Signature) (libcore.Address, error)
+}
+
+// SignData signs the data with the key, as Sign does for the data of a
+// Signable.
+func (service *CryptoService) SignData(p libaccount.Key, data []byte) (libcore.PublicKey, libcore.Signature, error) {
+	publicKey, err := p.GetPublic()
+	if err != nil {
+		return nil, nil, err
+	}
+	publicBytes, err := publicKey.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	hash, err := service.Hash(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	signature, err := p.Sign(hash, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return libcore.PublicKey(publicBytes), signature, nil
+}
+
+// VerifySignature checks the signature of the data, and returns the address
+// of the public key.
+func (service *CryptoService) VerifySignature(data []byte, publicKey libcore.PublicKey, signature libcore.Signature) (libcore.Address, error) {
+	_, p, err := as.NewPublicFromBytes([]byte(publicKey))
+	if err != nil {
+		return nil, err
+	}
+	a, err := p.GenerateAddress()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := service.Hash(data)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := p.Verify(hash, data, signature)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("error signature")
+	}
+	return a, nil
+}
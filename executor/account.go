@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"github.com/tokentransfer/chain/account"
+	"github.com/tokentransfer/chain/block"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+var as = &account.AccountService{}
+
+func init() {
+	Register(block.SET_REGULAR_KEY, &setRegularKey{})
+	Register(block.ACCOUNT_SET, &accountSet{})
+}
+
+// hasOtherKey returns whether the account can sign without its master key,
+// with its regular key or its signer list.
+func hasOtherKey(ctx *Context, native *block.AccountState) bool {
+	if len(native.RegularKey) > 0 {
+		return true
+	}
+	list := ctx.GetSignerListState(native.Account)
+	return list != nil && list.Quorum > 0
+}
+
+// setRegularKey sets the regular key of its account to the public key of its
+// SetRegularKey payload, or removes it. The key can't be the master key, and
+// can't be removed while the master key is disabled and there is no signer
+// list.
+type setRegularKey struct{}
+
+func (h *setRegularKey) Accounts(tx *block.Transaction) []libcore.Address {
+	return []libcore.Address{tx.Account}
+}
+
+func (h *setRegularKey) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	params := &block.SetRegularKey{}
+	err := params.UnmarshalBinary(tx.Payload)
+	if err != nil {
+		return block.TrBAD_PARAMETER, nil
+	}
+	if len(params.PublicKey) > 0 {
+		_, p, err := as.NewPublicFromBytes([]byte(params.PublicKey))
+		if err != nil {
+			return block.TrBAD_PARAMETER, nil
+		}
+		a, err := p.GenerateAddress()
+		if err != nil || libcore.Equals(a, tx.Account) {
+			return block.TrBAD_PARAMETER, nil
+		}
+	}
+
+	native := ctx.GetAccountState(tx.Account, nil, nil)
+	native.RegularKey = params.PublicKey
+	if native.Flags&block.ACCOUNT_DISABLE_MASTER != 0 && !hasOtherKey(ctx, native) {
+		return block.TrNO_KEY, nil
+	}
+	ctx.PutState(native)
+	return block.TrSUCCESS, nil
+}
+
+// accountSet changes the flags of its account with its FlagsChange payload.
+// The master key can only be disabled while the account has a regular key or
+// a signer list.
+type accountSet struct{}
+
+func (h *accountSet) Accounts(tx *block.Transaction) []libcore.Address {
+	return []libcore.Address{tx.Account}
+}
+
+func (h *accountSet) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	params := &block.FlagsChange{}
+	err := params.UnmarshalBinary(tx.Payload)
+	if err != nil || (params.SetFlags|params.ClearFlags)&^block.ACCOUNT_FLAGS != 0 {
+		return block.TrBAD_PARAMETER, nil
+	}
+
+	native := ctx.GetAccountState(tx.Account, nil, nil)
+	native.Flags = params.Apply(native.Flags)
+	if native.Flags&block.ACCOUNT_DISABLE_MASTER != 0 && !hasOtherKey(ctx, native) {
+		return block.TrNO_KEY, nil
+	}
+	ctx.PutState(native)
+	return block.TrSUCCESS, nil
+}
@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/crypto"
+	"github.com/tokentransfer/chain/pool"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+// signWith signs the transaction of the account at the sequence with the key
+// of the signer.
+func signWith(t *testing.T, account *testAccount, sequence uint64, tx *block.Transaction, signer *testAccount) *block.Transaction {
+	tx.Account = account.address
+	tx.Sequence = sequence
+	err := (&crypto.CryptoService{}).Sign(signer.key, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestRegularKey(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 3, 100)
+	owner, regular, to := accounts[0], accounts[1], accounts[2]
+
+	publicKey := func(a *testAccount) libcore.PublicKey {
+		p, err := a.key.GetPublic()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return libcore.PublicKey(data)
+	}
+	setKey := func(key libcore.PublicKey) *block.Transaction {
+		payload, err := (&block.SetRegularKey{PublicKey: key}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &block.Transaction{
+			TransactionType: block.SET_REGULAR_KEY,
+			Payload:         payload,
+		}
+	}
+	setFlags := func(set, clear uint32) *block.Transaction {
+		payload, err := (&block.FlagsChange{SetFlags: set, ClearFlags: clear}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &block.Transaction{
+			TransactionType: block.ACCOUNT_SET,
+			Payload:         payload,
+		}
+	}
+	pay := func(v int64) *block.Transaction {
+		return &block.Transaction{
+			TransactionType: block.TRANSACTION,
+			Amount:          *amount(t, v),
+			Destination:     to.address,
+		}
+	}
+
+	e := NewExecutor(&crypto.CryptoService{}, 2)
+	r := execute(t, e, base, []libblock.Transaction{
+		owner.sign(t, setKey(publicKey(owner))),
+		owner.sign(t, setKey(libcore.PublicKey{1, 2, 3})),
+		owner.sign(t, setFlags(block.ACCOUNT_DISABLE_MASTER, 0)),
+		owner.sign(t, setKey(publicKey(regular))),
+		owner.sign(t, setFlags(block.ACCOUNT_DISABLE_MASTER, 0)),
+	})
+	checkResults(t, r, block.TrBAD_PARAMETER, block.TrBAD_PARAMETER, block.TrNO_KEY, block.TrSUCCESS, block.TrSUCCESS)
+	err := r.Flush(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := owner.sequence + 1
+	byRegular := signWith(t, owner, next, pay(10), regular)
+	p := pool.NewPool(e.Authorizer(base), 0)
+	if err := p.Add(byRegular); err != nil {
+		t.Errorf("pool refused transaction signed by the regular key: %v", err)
+	}
+	// neither the disabled master key nor another account's key can replace it
+	for _, signer := range []*testAccount{owner, to} {
+		replacement := pay(10)
+		replacement.Gas = 100
+		if err := p.Add(signWith(t, owner, next, replacement, signer)); err != pool.ErrBadSignature {
+			t.Errorf("pool added transaction signed by %s: %v", signer.address, err)
+		}
+	}
+
+	r = execute(t, e, base, []libblock.Transaction{
+		signWith(t, owner, next, pay(10), owner),
+		signWith(t, owner, next, pay(10), to),
+		byRegular,
+		signWith(t, owner, next+1, setKey(nil), regular),
+		signWith(t, owner, next+2, setFlags(0, block.ACCOUNT_DISABLE_MASTER), regular),
+		signWith(t, owner, next+3, pay(20), owner),
+	})
+	checkResults(t, r,
+		block.TrBAD_SIGNATURE,
+		block.TrBAD_SIGNATURE,
+		block.TrSUCCESS,
+		block.TrNO_KEY,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+	)
+	checkBalance(t, r, to, *amount(t, 0), 130)
+}
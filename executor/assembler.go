@@ -0,0 +1,118 @@
+package executor
+
+import (
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/node"
+	"github.com/tokentransfer/chain/pool"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+)
+
+// Assembler builds the next block from the transactions of a pool.
+type Assembler struct {
+	executor *Executor
+	pool     *pool.Pool
+	baseFees *BaseFeeSchedule
+
+	// MaxTransactions limits the transactions of a block, if not 0.
+	MaxTransactions int
+}
+
+// NewAssembler returns an assembler whose blocks have no base fee if
+// baseFees is nil.
+func NewAssembler(e *Executor, p *pool.Pool, baseFees *BaseFeeSchedule) *Assembler {
+	return &Assembler{
+		executor: e,
+		pool:     p,
+		baseFees: baseFees,
+	}
+}
+
+// Assemble sets the base fee of the block after parent, or of the first
+// block if parent is nil, and executes the pending transactions with the
+// most gas above it. The transactions left out of the block which can never
+// be applied, with a bad signature or a used sequence, are dropped from the
+// pool. The others stay, as their account may be funded or reach their
+// sequence, or the base fee fall, and the transactions of the block are
+// removed when it is committed, by Remove. The hashes of the block are left
+// to the caller.
+func (a *Assembler) Assemble(base node.StateReader, parent libblock.Block, timestamp int64) (*block.Block, *Result, error) {
+	if parent != nil {
+		// the base fee depends on the transactions of the parent
+		err := block.LoadBody(parent)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	env := Env{
+		Timestamp: timestamp,
+		BaseFee:   a.baseFees.Next(parent),
+	}
+	b := &block.Block{
+		Timestamp: timestamp,
+		BaseFee:   env.BaseFee,
+	}
+	if parent != nil {
+		env.BlockIndex = parent.GetIndex() + 1
+		b.BlockIndex = env.BlockIndex
+		b.ParentHash = parent.GetHash()
+	}
+
+	a.pool.SetBaseFee(env.BaseFee)
+	txs := a.pool.Pending(a.MaxTransactions)
+	result, err := a.executor.Execute(base, env, txs)
+	if err != nil {
+		return nil, nil, err
+	}
+	dropped, err := a.hopeless(base, txs, result)
+	if err != nil {
+		return nil, nil, err
+	}
+	a.pool.Drop(dropped)
+	b.Transactions = result.Transactions
+	b.States = result.GetStates()
+	return b, result, nil
+}
+
+// hopeless returns the transactions left out of the block which would fail
+// the same way in the next blocks.
+func (a *Assembler) hopeless(base node.StateReader, txs []libblock.Transaction, result *Result) ([]libblock.Transaction, error) {
+	list := make([]libblock.Transaction, 0)
+	for i, tx := range txs {
+		switch result.Results[i] {
+		case block.TrBAD_SIGNATURE:
+			list = append(list, tx)
+		case block.TrBAD_SEQUENCE:
+			t := tx.(*block.Transaction)
+			key := core.GetAccountKey(t.Account, nil, nil, "-")
+			state, err := base.GetStateByTypeAndKey(block.ACCOUNT_STATE, key)
+			if err != nil {
+				if node.IsNonexists(err) {
+					continue
+				}
+				return nil, err
+			}
+			// a sequence in the future may be reached, a used one can't
+			if account, ok := state.(*block.AccountState); ok && t.Sequence <= account.Sequence {
+				list = append(list, tx)
+			}
+		}
+	}
+	return list, nil
+}
+
+// Remove drops the transactions of a committed block from the pool.
+func (a *Assembler) Remove(b libblock.Block) error {
+	err := block.LoadBody(b)
+	if err != nil {
+		return err
+	}
+	txs := b.GetTransactions()
+	list := make([]libblock.Transaction, len(txs))
+	for i, tx := range txs {
+		list[i] = tx.GetTransaction()
+	}
+	a.pool.Remove(list)
+	return nil
+}
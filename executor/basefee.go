@@ -0,0 +1,106 @@
+package executor
+
+import (
+	"math/big"
+
+	"github.com/tokentransfer/chain/core"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+// BaseFeeSchedule adjusts the base fee of every block to the fullness of its
+// parent: the base fee rises when the parent is fuller than Target, and falls
+// when it is emptier, by at most 1/Denominator of the parent base fee. The
+// base fee is charged to every transaction on top of its FeeSchedule fee and
+// is burned.
+type BaseFeeSchedule struct {
+	Initial     int64 // base fee of the first block
+	Min         int64 // lowest base fee
+	Target      int64 // target fullness of a block
+	ByGas       bool  // the fullness is the fees charged rather than the number of transactions
+	Denominator int64 // 8 if 0
+}
+
+// BaseFeeConfig is implemented by configs which adjust the base fee.
+type BaseFeeConfig interface {
+	GetBaseFeeSchedule() *BaseFeeSchedule
+}
+
+// GetBaseFeeSchedule returns the base fee schedule selected by the config, or
+// nil if blocks have no base fee.
+func GetBaseFeeSchedule(c libcore.Config) *BaseFeeSchedule {
+	if bc, ok := c.(BaseFeeConfig); ok {
+		return bc.GetBaseFeeSchedule()
+	}
+	return nil
+}
+
+// Fullness returns the number of transactions of the block, or the fees of
+// their FeeSchedule if ByGas is set. The base fee of the block is left out of
+// the fees, or a higher base fee would make the block look fuller and raise
+// the next base fee again.
+func (s *BaseFeeSchedule) Fullness(b libblock.Block) int64 {
+	txs := b.GetTransactions()
+	if !s.ByGas {
+		return int64(len(txs))
+	}
+	baseFee := int64(0)
+	if bb, ok := b.(interface{ GetBaseFee() int64 }); ok {
+		baseFee = bb.GetBaseFee()
+	}
+	used := int64(0)
+	for _, tx := range txs {
+		if r, ok := tx.GetReceipt().(interface{ GetFee() int64 }); ok {
+			used += r.GetFee() - baseFee
+		}
+	}
+	return used
+}
+
+// Next returns the base fee of the block after parent, or Initial if parent
+// is nil.
+func (s *BaseFeeSchedule) Next(parent libblock.Block) int64 {
+	if s == nil {
+		return 0
+	}
+	if parent == nil {
+		return s.max(s.Initial)
+	}
+	fee := int64(0)
+	if p, ok := parent.(interface{ GetBaseFee() int64 }); ok {
+		fee = p.GetBaseFee()
+	}
+	fee = s.max(fee)
+	if s.Target <= 0 {
+		return fee
+	}
+
+	used := s.Fullness(parent)
+	if used == s.Target {
+		return fee
+	}
+	denominator := s.Denominator
+	if denominator <= 0 {
+		denominator = 8
+	}
+	delta := new(big.Int).Mul(big.NewInt(fee), big.NewInt(used-s.Target))
+	delta.Quo(delta, big.NewInt(s.Target))
+	delta.Quo(delta, big.NewInt(denominator))
+	if used > s.Target && delta.Sign() == 0 {
+		// a full block always raises the base fee
+		delta.SetInt64(1)
+	}
+	next := delta.Add(delta, big.NewInt(fee))
+	if next.Cmp(big.NewInt(core.MAX_VALUE)) > 0 {
+		return core.MAX_VALUE
+	}
+	return s.max(next.Int64())
+}
+
+func (s *BaseFeeSchedule) max(fee int64) int64 {
+	if fee < s.Min {
+		return s.Min
+	}
+	return fee
+}
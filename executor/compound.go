@@ -0,0 +1,96 @@
+package executor
+
+import (
+	"github.com/tokentransfer/chain/block"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+// MAX_OPERATIONS is the largest number of operations of a compound
+// transaction.
+const MAX_OPERATIONS = 16
+
+func init() {
+	Register(block.COMPOUND, &compound{})
+}
+
+// operations returns the operations of the Compound payload of the
+// transaction, with the account and sequence of the transaction. An
+// operation can't be signed or compound itself.
+func operations(tx *block.Transaction) ([]*block.Transaction, libblock.TransactionResult) {
+	params := &block.Compound{}
+	err := params.UnmarshalBinary(tx.Payload)
+	if err != nil || len(params.Operations) == 0 || len(params.Operations) > MAX_OPERATIONS {
+		return nil, block.TrBAD_PARAMETER
+	}
+	ops := make([]*block.Transaction, len(params.Operations))
+	for i, op := range params.Operations {
+		if op.TransactionType == block.COMPOUND {
+			return nil, block.TrBAD_PARAMETER
+		}
+		if len(op.PublicKey) > 0 || len(op.Signature) > 0 || len(op.Signers) > 0 || op.Sequence != 0 || op.Gas != 0 {
+			return nil, block.TrBAD_PARAMETER
+		}
+		if op.Account != nil && !libcore.Equals(op.Account, tx.Account) {
+			return nil, block.TrBAD_ACCOUNT
+		}
+		if _, h := getHandler(op); h == nil {
+			return nil, block.TrBAD_TRANSACTION
+		}
+		op.Account = tx.Account
+		op.Sequence = tx.Sequence
+		ops[i] = op
+	}
+	return ops, block.TrSUCCESS
+}
+
+// compound applies the operations of its Compound payload in order, and
+// records their results and the preimages they revealed in the receipt. If an
+// operation fails, the others still run to report their results, then the
+// transaction fails with TrCOMPOUND and none of them is applied.
+type compound struct{}
+
+func (h *compound) Accounts(tx *block.Transaction) []libcore.Address {
+	accounts := []libcore.Address{tx.Account}
+	ops, _ := operations(tx)
+	for _, op := range ops {
+		_, oh := getHandler(op)
+		accounts = append(accounts, oh.Accounts(op)...)
+	}
+	return accounts
+}
+
+func (h *compound) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	ops, result := operations(tx)
+	if result != block.TrSUCCESS {
+		return result, nil
+	}
+
+	receipt := ctx.GetReceipt()
+	defer func() {
+		ctx.operation = 0
+	}()
+	failed := false
+	for i, op := range ops {
+		_, oh := getHandler(op)
+		ctx.operation = uint32(i)
+		id := ctx.overlay.Snapshot()
+		result, err := oh.Apply(ctx, op)
+		if err != nil {
+			return result, err
+		}
+		if result != block.TrSUCCESS {
+			ctx.overlay.RevertToSnapshot(id)
+			failed = true
+		}
+		receipt.Results = append(receipt.Results, result)
+		receipt.Preimages = append(receipt.Preimages, receipt.Preimage)
+		receipt.Preimage = nil
+	}
+	if failed {
+		receipt.Preimages = nil
+		return block.TrCOMPOUND, nil
+	}
+	return block.TrSUCCESS, nil
+}
@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/crypto"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+)
+
+func TestCompound(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 3, 100)
+	issuer, a, b := accounts[0], accounts[1], accounts[2]
+
+	batch := func(from *testAccount, gas int64, ops ...*block.Transaction) *block.Transaction {
+		payload, err := (&block.Compound{Operations: ops}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return from.sign(t, &block.Transaction{
+			TransactionType: block.COMPOUND,
+			Gas:             gas,
+			Payload:         payload,
+		})
+	}
+	pay := func(to *testAccount, v int64) *block.Transaction {
+		return &block.Transaction{
+			TransactionType: block.TRANSACTION,
+			Amount:          *amount(t, v),
+			Destination:     to.address,
+		}
+	}
+	issue := func(symbol string, supply string) *block.Transaction {
+		payload, err := (&block.IssueCurrency{Name: symbol, Symbol: symbol, TotalSupply: supply}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &block.Transaction{
+			TransactionType: block.ISSUE_CURRENCY,
+			Payload:         payload,
+		}
+	}
+	op := func(tt libblock.TransactionType, v int64) *block.Transaction {
+		return &block.Transaction{
+			TransactionType: tt,
+			Amount:          issued(t, v, "CNY", issuer),
+		}
+	}
+	nested := &block.Transaction{TransactionType: block.COMPOUND}
+	foreign := pay(b, 10)
+	foreign.Account = b.address
+
+	e := NewExecutor(&crypto.CryptoService{}, 2)
+	r := execute(t, e, base, []libblock.Transaction{
+		batch(a, 0, pay(b, 10), pay(issuer, 20)),
+		batch(a, 0, pay(b, 10), pay(issuer, 500), pay(b, 5)),
+		batch(a, 0),
+		batch(a, 0, pay(b, 10), nested),
+		batch(a, 0, foreign),
+		batch(issuer, 0, issue("CNY", "1000"), op(block.MINT, 100), op(block.BURN, 50)),
+	})
+	checkResults(t, r,
+		block.TrSUCCESS,
+		block.TrCOMPOUND,
+		block.TrBAD_PARAMETER,
+		block.TrBAD_PARAMETER,
+		block.TrBAD_ACCOUNT,
+		block.TrSUCCESS,
+	)
+	checkBalance(t, r, a, *amount(t, 0), 70)
+	checkBalance(t, r, b, *amount(t, 0), 110)
+	checkBalance(t, r, issuer, issued(t, 0, "CNY", issuer), 1050)
+
+	expected := []libblock.TransactionResult{block.TrSUCCESS, block.TrBAD_AMOUNT, block.TrSUCCESS}
+	data, err := r.Transactions[1].GetReceipt().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt := &block.Receipt{}
+	err = receipt.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := receipt.GetResults()
+	if len(results) != len(expected) {
+		t.Fatalf("receipt has %d results, expected %d", len(results), len(expected))
+	}
+	for i, result := range expected {
+		if results[i] != result {
+			t.Errorf("operation %d has result %d, expected %d", i, results[i], result)
+		}
+	}
+
+	// the fee is the base fee of the compound transaction and of each
+	// operation, and a transaction without enough gas keeps its sequence
+	e.SetFeeSchedule(&FeeSchedule{BaseFee: 1})
+	underpaid := batch(b, 2, pay(a, 10), pay(a, 10))
+	b.sequence--
+	r = execute(t, e, base, []libblock.Transaction{
+		underpaid,
+		batch(b, 3, pay(a, 10), pay(a, 10)),
+	})
+	checkResults(t, r, block.TrINSUFF_GAS, block.TrSUCCESS)
+	if len(r.Transactions) != 1 {
+		t.Fatalf("block has %d transactions, expected 1", len(r.Transactions))
+	}
+	if fee := r.Transactions[0].GetReceipt().(*block.Receipt).GetFee(); fee != 3 {
+		t.Errorf("compound transaction paid %d, expected 3", fee)
+	}
+}
@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/node"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+	libcrypto "github.com/tokentransfer/interfaces/crypto"
+)
+
+// Env describes the block the transactions are executed in.
+type Env struct {
+	BlockIndex uint64
+	Timestamp  int64
+	BaseFee    int64 // charged to every transaction and burned
+}
+
+// Context is given to the handlers to read and write the states of the
+// block being executed.
+type Context struct {
+	Env
+
+	crypto    libcrypto.CryptoService
+	overlay   *node.Overlay
+	receipt   *block.Receipt
+	operation uint32 // index of the operation being applied of a compound transaction
+	err       error  // the first failure to read a state, which fails the block
+}
+
+func (ctx *Context) GetCrypto() libcrypto.CryptoService {
+	return ctx.crypto
+}
+
+// GetReceipt returns the receipt of the transaction being applied, for the
+// handlers to record their outputs. The executor fills in the other fields.
+func (ctx *Context) GetReceipt() *block.Receipt {
+	return ctx.receipt
+}
+
+// GetState returns the state with the type and key, or nil if there is none.
+// If the state can't be read, nil is returned too and the error is kept, so
+// that the executor fails the block.
+func (ctx *Context) GetState(stateType libblock.StateType, stateKey string) libblock.State {
+	state, err := ctx.overlay.GetState(stateType, stateKey)
+	if err != nil {
+		if !node.IsNonexists(err) && ctx.err == nil {
+			ctx.err = err
+		}
+		return nil
+	}
+	return state
+}
+
+// PutState writes the state in the block.
+func (ctx *Context) PutState(state libblock.State) {
+	state.SetBlockIndex(ctx.BlockIndex)
+	ctx.overlay.PutState(state)
+}
+
+// GetAccountState returns a copy of the balance of the account in the
+// currency, or nil if the account has none.
+func (ctx *Context) GetAccountState(account libcore.Address, currency *libcore.Symbol, issuer libcore.Address) *block.AccountState {
+	key := core.GetAccountKey(account, currency, issuer, "-")
+	state, ok := ctx.GetState(block.ACCOUNT_STATE, key).(*block.AccountState)
+	if !ok {
+		return nil
+	}
+	s := *state
+	return &s
+}
+
+// GetCurrencyState returns a copy of the currency issued by the issuer, or
+// nil if there is none.
+func (ctx *Context) GetCurrencyState(currency *libcore.Symbol, issuer libcore.Address) *block.CurrencyState {
+	key := core.GetCurrencyKey(currency, issuer, "-")
+	state, ok := ctx.GetState(block.CURRENCY_STATE, key).(*block.CurrencyState)
+	if !ok {
+		return nil
+	}
+	s := *state
+	return &s
+}
+
+// GetTrustLineState returns a copy of the trust line of the account for the
+// currency of the issuer, or nil if there is none.
+func (ctx *Context) GetTrustLineState(account libcore.Address, currency *libcore.Symbol, issuer libcore.Address) *block.TrustLineState {
+	key := core.GetAccountKey(account, currency, issuer, "-")
+	state, ok := ctx.GetState(block.TRUST_LINE_STATE, key).(*block.TrustLineState)
+	if !ok {
+		return nil
+	}
+	s := *state
+	return &s
+}
+
+// GetSignerListState returns a copy of the signer list of the account, or
+// nil if there is none.
+func (ctx *Context) GetSignerListState(account libcore.Address) *block.SignerListState {
+	state, ok := ctx.GetState(block.SIGNER_LIST_STATE, account.String()).(*block.SignerListState)
+	if !ok {
+		return nil
+	}
+	s := *state
+	return &s
+}
+
+// GetEscrowState returns a copy of the escrow created by the transaction of
+// the account with the sequence, or by its operation, or nil if there is
+// none.
+func (ctx *Context) GetEscrowState(account libcore.Address, sequence uint64, operation uint32) *block.EscrowState {
+	state, ok := ctx.GetState(block.ESCROW_STATE, block.GetEscrowKey(account, sequence, operation)).(*block.EscrowState)
+	if !ok {
+		return nil
+	}
+	s := *state
+	return &s
+}
+
+// NewAccountState returns a zero balance of the account in the currency.
+func NewAccountState(account libcore.Address, currency *libcore.Symbol, issuer libcore.Address) *block.AccountState {
+	return &block.AccountState{
+		State: block.State{
+			Account:   account,
+			StateType: block.ACCOUNT_STATE,
+		},
+		Amount: core.Amount{
+			Currency: currency,
+			Issuer:   issuer,
+		},
+	}
+}
@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"github.com/tokentransfer/chain/block"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+func init() {
+	Register(block.CURRENCY_SET, &currencySet{})
+	Register(block.TRUST_CONTROL, &trustControl{})
+	Register(block.CLAWBACK, &clawback{})
+}
+
+// issuedCurrency returns the currency of the amount of the transaction, which
+// must be issued by its account.
+func issuedCurrency(ctx *Context, tx *block.Transaction) (*block.CurrencyState, libblock.TransactionResult) {
+	amount := tx.Amount
+	if amount.IsNative() || !libcore.Equals(amount.Issuer, tx.Account) {
+		return nil, block.TrBAD_ACCOUNT
+	}
+	currency := ctx.GetCurrencyState(amount.Currency, amount.Issuer)
+	if currency == nil {
+		return nil, block.TrNO_ENTRY
+	}
+	return currency, block.TrSUCCESS
+}
+
+// addTrustLine counts a new trust line to the currency.
+func addTrustLine(ctx *Context, currency *block.CurrencyState) {
+	currency.TrustLines++
+	ctx.PutState(currency)
+}
+
+// currencySet changes the flags of a currency of its account with its
+// FlagsChange payload. CURRENCY_ALLOW_CLAWBACK can't be cleared, and can only
+// be set before any account trusts the currency, so holders know whether
+// their balances may be taken back.
+type currencySet struct{}
+
+func (h *currencySet) Accounts(tx *block.Transaction) []libcore.Address {
+	return []libcore.Address{tx.Account}
+}
+
+func (h *currencySet) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	params := &block.FlagsChange{}
+	err := params.UnmarshalBinary(tx.Payload)
+	if err != nil || (params.SetFlags|params.ClearFlags)&^block.CURRENCY_FLAGS != 0 {
+		return block.TrBAD_PARAMETER, nil
+	}
+	currency, result := issuedCurrency(ctx, tx)
+	if result != block.TrSUCCESS {
+		return result, nil
+	}
+	flags := params.Apply(currency.Flags)
+	if currency.Flags&block.CURRENCY_ALLOW_CLAWBACK != 0 && flags&block.CURRENCY_ALLOW_CLAWBACK == 0 {
+		return block.TrNO_PERMISSION, nil
+	}
+	if currency.Flags&block.CURRENCY_ALLOW_CLAWBACK == 0 && flags&block.CURRENCY_ALLOW_CLAWBACK != 0 && currency.TrustLines > 0 {
+		return block.TrNO_PERMISSION, nil
+	}
+	currency.Flags = flags
+	ctx.PutState(currency)
+	return block.TrSUCCESS, nil
+}
+
+// trustControl changes the flags of the trust line of the destination for a
+// currency of its account with its FlagsChange payload, creating the trust
+// line with no limit if needed, so holders may be authorized in advance.
+type trustControl struct{}
+
+func (h *trustControl) Accounts(tx *block.Transaction) []libcore.Address {
+	return []libcore.Address{tx.Account, tx.Destination}
+}
+
+func (h *trustControl) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	params := &block.FlagsChange{}
+	err := params.UnmarshalBinary(tx.Payload)
+	if err != nil || (params.SetFlags|params.ClearFlags)&^block.TRUST_LINE_FLAGS != 0 {
+		return block.TrBAD_PARAMETER, nil
+	}
+	if tx.Destination == nil || libcore.Equals(tx.Destination, tx.Account) {
+		return block.TrBAD_PARAMETER, nil
+	}
+	currency, result := issuedCurrency(ctx, tx)
+	if result != block.TrSUCCESS {
+		return result, nil
+	}
+
+	limit := currency.TotalSupply.ZeroClone()
+	line := ctx.GetTrustLineState(tx.Destination, limit.Currency, limit.Issuer)
+	if line == nil {
+		line = &block.TrustLineState{
+			State: block.State{
+				Account:   tx.Destination,
+				StateType: block.TRUST_LINE_STATE,
+			},
+			Limit: *limit,
+		}
+		addTrustLine(ctx, currency)
+	}
+	line.Flags = params.Apply(line.Flags)
+	ctx.PutState(line)
+	return block.TrSUCCESS, nil
+}
+
+// clawback moves the amount from the balance of the destination back to its
+// issuer, the account of the transaction, if the currency allows it. Frozen
+// balances may be clawed back.
+type clawback struct{}
+
+func (h *clawback) Accounts(tx *block.Transaction) []libcore.Address {
+	return []libcore.Address{tx.Account, tx.Destination}
+}
+
+func (h *clawback) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	amount := tx.Amount
+	if !amount.IsPositive() {
+		return block.TrBAD_AMOUNT, nil
+	}
+	if tx.Destination == nil || libcore.Equals(tx.Destination, tx.Account) {
+		return block.TrBAD_PARAMETER, nil
+	}
+	currency, result := issuedCurrency(ctx, tx)
+	if result != block.TrSUCCESS {
+		return result, nil
+	}
+	if currency.Flags&block.CURRENCY_ALLOW_CLAWBACK == 0 {
+		return block.TrNO_PERMISSION, nil
+	}
+
+	from := ctx.GetAccountState(tx.Destination, amount.Currency, amount.Issuer)
+	if from == nil || from.Amount.Less(amount) {
+		return block.TrBAD_AMOUNT, nil
+	}
+	to := ctx.GetAccountState(tx.Account, amount.Currency, amount.Issuer)
+	if to == nil {
+		to = NewAccountState(tx.Account, amount.Currency, amount.Issuer)
+	}
+	return transfer(ctx, from, to, amount)
+}
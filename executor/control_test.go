@@ -0,0 +1,155 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/crypto"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+)
+
+func TestIssuerControls(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 3, 100)
+	issuer, alice, bob := accounts[0], accounts[1], accounts[2]
+
+	usd := func(v int64) core.Amount {
+		return issued(t, v, "USD", issuer)
+	}
+	op := func(from *testAccount, tt libblock.TransactionType, to *testAccount, a core.Amount) *block.Transaction {
+		tx := &block.Transaction{
+			TransactionType: tt,
+			Amount:          a,
+		}
+		if to != nil {
+			tx.Destination = to.address
+		}
+		return from.sign(t, tx)
+	}
+	flags := func(from *testAccount, tt libblock.TransactionType, to *testAccount, set uint32, clear uint32) *block.Transaction {
+		payload, err := (&block.FlagsChange{SetFlags: set, ClearFlags: clear}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := &block.Transaction{
+			TransactionType: tt,
+			Amount:          usd(0),
+			Payload:         payload,
+		}
+		if to != nil {
+			tx.Destination = to.address
+		}
+		return from.sign(t, tx)
+	}
+
+	txs := []libblock.Transaction{
+		issuer.issueWithFlags(t, "USD", "1000", 0, block.CURRENCY_REQUIRE_AUTH|block.CURRENCY_ALLOW_CLAWBACK),
+		op(alice, block.TRUST_SET, nil, usd(100)),
+		op(bob, block.TRUST_SET, nil, usd(100)),
+		op(issuer, block.TRANSACTION, alice, usd(10)),
+		flags(issuer, block.TRUST_CONTROL, alice, block.TRUST_LINE_AUTHORIZED, 0),
+		op(issuer, block.TRANSACTION, alice, usd(50)),
+		op(alice, block.TRANSACTION, bob, usd(10)),
+		flags(issuer, block.TRUST_CONTROL, bob, block.TRUST_LINE_AUTHORIZED, 0),
+		op(alice, block.TRANSACTION, bob, usd(10)),
+		flags(issuer, block.TRUST_CONTROL, alice, block.TRUST_LINE_FROZEN, 0),
+		op(alice, block.TRANSACTION, bob, usd(5)),
+		op(bob, block.TRANSACTION, alice, usd(5)),
+		op(issuer, block.TRANSACTION, alice, usd(5)),
+		op(alice, block.TRANSACTION, issuer, usd(5)),
+		op(issuer, block.CLAWBACK, alice, usd(20)),
+		op(issuer, block.CLAWBACK, alice, usd(30)),
+		flags(issuer, block.CURRENCY_SET, nil, block.CURRENCY_FROZEN, 0),
+		op(bob, block.TRANSACTION, issuer, usd(1)),
+		op(bob, block.BURN, nil, usd(1)),
+		flags(issuer, block.CURRENCY_SET, nil, 0, block.CURRENCY_ALLOW_CLAWBACK),
+		flags(alice, block.TRUST_CONTROL, bob, block.TRUST_LINE_FROZEN, 0),
+		flags(issuer, block.CURRENCY_SET, nil, 1<<10, 0),
+	}
+
+	r := execute(t, NewExecutor(&crypto.CryptoService{}, 2), base, txs)
+	checkResults(t, r,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrNO_AUTH,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrNO_AUTH,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrFROZEN,
+		block.TrFROZEN,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrBAD_AMOUNT,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrFROZEN,
+		block.TrNO_PERMISSION,
+		block.TrBAD_ACCOUNT,
+		block.TrBAD_PARAMETER,
+	)
+	checkBalance(t, r, alice, usd(0), 20)
+	checkBalance(t, r, bob, usd(0), 9)
+	checkBalance(t, r, issuer, usd(0), 971)
+}
+
+func TestClawbackFlag(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 3, 100)
+	issuer, alice, bob := accounts[0], accounts[1], accounts[2]
+
+	trust := func(from *testAccount, symbol string) *block.Transaction {
+		return from.sign(t, &block.Transaction{
+			TransactionType: block.TRUST_SET,
+			Amount:          issued(t, 100, symbol, issuer),
+		})
+	}
+	flags := func(tt libblock.TransactionType, symbol string, to *testAccount, set uint32) *block.Transaction {
+		payload, err := (&block.FlagsChange{SetFlags: set}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := &block.Transaction{
+			TransactionType: tt,
+			Amount:          issued(t, 0, symbol, issuer),
+			Payload:         payload,
+		}
+		if to != nil {
+			tx.Destination = to.address
+		}
+		return issuer.sign(t, tx)
+	}
+
+	// clawback may be allowed until a trust line is created by its holder or
+	// by the issuer
+	r := execute(t, NewExecutor(&crypto.CryptoService{}, 2), base, []libblock.Transaction{
+		issuer.issue(t, "USD", "1000", 0),
+		issuer.issue(t, "EUR", "1000", 0),
+		issuer.issue(t, "CNY", "1000", 0),
+		flags(block.CURRENCY_SET, "USD", nil, block.CURRENCY_ALLOW_CLAWBACK),
+		trust(alice, "EUR"),
+		flags(block.CURRENCY_SET, "EUR", nil, block.CURRENCY_ALLOW_CLAWBACK),
+		flags(block.TRUST_CONTROL, "CNY", bob, block.TRUST_LINE_AUTHORIZED),
+		flags(block.CURRENCY_SET, "CNY", nil, block.CURRENCY_ALLOW_CLAWBACK),
+		trust(bob, "USD"),
+		flags(block.CURRENCY_SET, "USD", nil, block.CURRENCY_ALLOW_CLAWBACK),
+	})
+	checkResults(t, r,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrNO_PERMISSION,
+		block.TrSUCCESS,
+		block.TrNO_PERMISSION,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+	)
+}
@@ -0,0 +1,144 @@
+package executor
+
+import (
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/core"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+func init() {
+	Register(block.ISSUE_CURRENCY, &issueCurrency{})
+	Register(block.MINT, &mint{})
+	Register(block.BURN, &burn{})
+}
+
+// issueCurrency creates the currency of its IssueCurrency payload, and
+// credits the total supply to the issuer.
+type issueCurrency struct{}
+
+func (h *issueCurrency) Accounts(tx *block.Transaction) []libcore.Address {
+	return []libcore.Address{tx.Account}
+}
+
+func (h *issueCurrency) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	params := &block.IssueCurrency{}
+	err := params.UnmarshalBinary(tx.Payload)
+	if err != nil {
+		return block.TrBAD_PARAMETER, nil
+	}
+	symbol, err := libcore.NewSymbol(params.Symbol)
+	if err != nil || symbol.String() == core.GetSystemCode() {
+		return block.TrBAD_PARAMETER, nil
+	}
+	if ctx.GetCurrencyState(symbol, tx.Account) != nil {
+		return block.TrALREADY, nil
+	}
+	if params.Flags&^block.CURRENCY_FLAGS != 0 {
+		return block.TrBAD_PARAMETER, nil
+	}
+	supply, err := core.CheckSupply("total supply", params.TotalSupply, int64(params.Decimals))
+	if err != nil || supply < 0 {
+		return block.TrBAD_AMOUNT, nil
+	}
+
+	total := nativeAmount(supply)
+	total.Currency = symbol
+	total.Issuer = tx.Account
+	ctx.PutState(&block.CurrencyState{
+		State: block.State{
+			Account:   tx.Account,
+			StateType: block.CURRENCY_STATE,
+		},
+		Name:        params.Name,
+		Symbol:      params.Symbol,
+		Decimals:    params.Decimals,
+		TotalSupply: total,
+		Flags:       params.Flags,
+	})
+	return credit(ctx, tx.Account, total)
+}
+
+// mint adds the amount to the total supply of a currency of the account, and
+// credits it to the account.
+type mint struct{}
+
+func (h *mint) Accounts(tx *block.Transaction) []libcore.Address {
+	return []libcore.Address{tx.Account}
+}
+
+func (h *mint) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	amount := tx.Amount
+	if !amount.IsPositive() {
+		return block.TrBAD_AMOUNT, nil
+	}
+	if amount.IsNative() || !libcore.Equals(amount.Issuer, tx.Account) {
+		return block.TrBAD_ACCOUNT, nil
+	}
+	currency := ctx.GetCurrencyState(amount.Currency, amount.Issuer)
+	if currency == nil {
+		return block.TrNO_ENTRY, nil
+	}
+	total, err := currency.TotalSupply.Add(amount)
+	if err != nil {
+		return block.TrBAD_AMOUNT, nil
+	}
+	currency.TotalSupply = *total
+	ctx.PutState(currency)
+	return credit(ctx, tx.Account, amount)
+}
+
+// burn removes the amount from the balance of the account and from the total
+// supply of its currency.
+type burn struct{}
+
+func (h *burn) Accounts(tx *block.Transaction) []libcore.Address {
+	return []libcore.Address{tx.Account, tx.Amount.Issuer}
+}
+
+func (h *burn) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	amount := tx.Amount
+	if !amount.IsPositive() || amount.IsNative() {
+		return block.TrBAD_AMOUNT, nil
+	}
+	currency := ctx.GetCurrencyState(amount.Currency, amount.Issuer)
+	if currency == nil {
+		return block.TrNO_ENTRY, nil
+	}
+	if !libcore.Equals(tx.Account, amount.Issuer) && isFrozen(ctx, currency, tx.Account) {
+		return block.TrFROZEN, nil
+	}
+	from := ctx.GetAccountState(tx.Account, amount.Currency, amount.Issuer)
+	if from == nil || from.Amount.Less(amount) {
+		return block.TrBAD_AMOUNT, nil
+	}
+	total, err := currency.TotalSupply.Subtract(amount)
+	if err != nil || total.IsNegative() {
+		return block.TrBAD_AMOUNT, nil
+	}
+	debited, err := from.Amount.Subtract(amount)
+	if err != nil {
+		return block.TrBAD_AMOUNT, nil
+	}
+	currency.TotalSupply = *total
+	from.Amount = *debited
+	ctx.PutState(currency)
+	ctx.PutState(from)
+	return block.TrSUCCESS, nil
+}
+
+// credit adds the amount to the balance of the account in its currency.
+func credit(ctx *Context, account libcore.Address, amount core.Amount) (libblock.TransactionResult, error) {
+	to := ctx.GetAccountState(account, amount.Currency, amount.Issuer)
+	if to == nil {
+		to = NewAccountState(account, amount.Currency, amount.Issuer)
+	}
+	credited, err := to.Amount.Add(amount)
+	if err != nil {
+		return block.TrBAD_AMOUNT, nil
+	}
+	to.Amount = *credited
+	ctx.PutState(to)
+	return block.TrSUCCESS, nil
+}
@@ -0,0 +1,144 @@
+package executor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/crypto"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+func issued(t *testing.T, v int64, symbol string, issuer *testAccount) core.Amount {
+	currency, err := libcore.NewSymbol(symbol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := amount(t, v)
+	a.Currency = currency
+	a.Issuer = issuer.address
+	return *a
+}
+
+func (a *testAccount) issue(t *testing.T, symbol string, supply string, decimals uint32) *block.Transaction {
+	return a.issueWithFlags(t, symbol, supply, decimals, 0)
+}
+
+func (a *testAccount) issueWithFlags(t *testing.T, symbol string, supply string, decimals uint32, flags uint32) *block.Transaction {
+	payload, err := (&block.IssueCurrency{
+		Name:        symbol + " token",
+		Symbol:      symbol,
+		Decimals:    decimals,
+		TotalSupply: supply,
+		Flags:       flags,
+	}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a.sign(t, &block.Transaction{
+		TransactionType: block.ISSUE_CURRENCY,
+		Payload:         payload,
+	})
+}
+
+// execute applies the transactions sequentially and in parallel, checks the
+// results are the same and returns them.
+func execute(t *testing.T, e *Executor, base mapStates, txs []libblock.Transaction) *Result {
+	return executeAt(t, e, base, Env{BlockIndex: 1}, txs)
+}
+
+func executeAt(t *testing.T, e *Executor, base mapStates, env Env, txs []libblock.Transaction) *Result {
+	sequential, err := e.ExecuteSequential(base, env, txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := e.Execute(base, env, txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bytes.Join(encodeResult(t, sequential), nil), bytes.Join(encodeResult(t, parallel), nil)) {
+		t.Fatal("parallel execution differs")
+	}
+	return sequential
+}
+
+func checkResults(t *testing.T, r *Result, results ...libblock.TransactionResult) {
+	for i, result := range results {
+		actual := r.Results[i]
+		if actual != result {
+			t.Errorf("transaction %d has result %d, expected %d", i, actual, result)
+		}
+	}
+}
+
+func checkBalance(t *testing.T, r *Result, account *testAccount, a core.Amount, expected int64) {
+	key := core.GetAccountKey(account.address, a.Currency, a.Issuer, "-")
+	state, err := r.overlay.GetState(block.ACCOUNT_STATE, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := state.(*block.AccountState).Amount.Value.Value(); v != expected {
+		t.Errorf("%s has %d, expected %d", key, v, expected)
+	}
+}
+
+func TestIssueCurrency(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 2, 100)
+	issuer, holder := accounts[0], accounts[1]
+
+	op := func(from *testAccount, tt libblock.TransactionType, a core.Amount) *block.Transaction {
+		return from.sign(t, &block.Transaction{
+			TransactionType: tt,
+			Amount:          a,
+		})
+	}
+	send := func(from *testAccount, to *testAccount, a core.Amount) *block.Transaction {
+		return from.sign(t, &block.Transaction{
+			TransactionType: block.TRANSACTION,
+			Amount:          a,
+			Destination:     to.address,
+		})
+	}
+	txs := []libblock.Transaction{
+		issuer.issue(t, "USD", "1000.5", 2),
+		issuer.issue(t, "USD", "1", 2),
+		issuer.issue(t, "EUR", "1.234", 2),
+		op(issuer, block.MINT, issued(t, 50, "USD", issuer)),
+		op(holder, block.MINT, issued(t, 50, "USD", issuer)),
+		op(issuer, block.MINT, issued(t, 50, "EUR", issuer)),
+		op(holder, block.TRUST_SET, issued(t, 1000, "USD", issuer)),
+		send(issuer, holder, issued(t, 100, "USD", issuer)),
+		op(holder, block.BURN, issued(t, 40, "USD", issuer)),
+		op(holder, block.BURN, issued(t, 100, "USD", issuer)),
+	}
+
+	r := execute(t, NewExecutor(&crypto.CryptoService{}, 2), base, txs)
+	checkResults(t, r,
+		block.TrSUCCESS,
+		block.TrALREADY,
+		block.TrBAD_AMOUNT,
+		block.TrSUCCESS,
+		block.TrBAD_ACCOUNT,
+		block.TrNO_ENTRY,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrBAD_AMOUNT,
+	)
+
+	usd := issued(t, 0, "USD", issuer)
+	checkBalance(t, r, issuer, usd, 100000)
+	checkBalance(t, r, holder, usd, 60)
+	state, err := r.overlay.GetState(block.CURRENCY_STATE, core.GetCurrencyKey(usd.Currency, usd.Issuer, "-"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	currency := state.(*block.CurrencyState)
+	if v := currency.TotalSupply.Value.Value(); v != 100060 || currency.Name != "USD token" || currency.Decimals != 2 {
+		t.Errorf("currency %s %d has supply %d", currency.Name, currency.Decimals, v)
+	}
+}
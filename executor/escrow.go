@@ -0,0 +1,193 @@
+package executor
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"github.com/tokentransfer/chain/block"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+func init() {
+	Register(block.ESCROW_CREATE, &escrowCreate{})
+	Register(block.ESCROW_FINISH, &escrowFinish{})
+	Register(block.ESCROW_CANCEL, &escrowCancel{})
+}
+
+// escrowCreate locks the amount of the transaction for its destination with
+// its EscrowCreate payload, keyed by its sequence and, in a compound
+// transaction, the index of the operation. The escrow needs a time to finish
+// or a SHA-256 hash lock, and a time to cancel after it, if any, which hasn't
+// passed. An escrow with a hash lock needs a time to cancel, so the amount
+// can go back.
+type escrowCreate struct{}
+
+func (h *escrowCreate) Accounts(tx *block.Transaction) []libcore.Address {
+	return []libcore.Address{tx.Account, tx.Amount.Issuer}
+}
+
+func (h *escrowCreate) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	params := &block.EscrowCreate{}
+	err := params.UnmarshalBinary(tx.Payload)
+	if err != nil || tx.Destination == nil || params.FinishAfter < 0 {
+		return block.TrBAD_PARAMETER, nil
+	}
+	if len(params.HashLock) == 0 && params.FinishAfter == 0 {
+		return block.TrBAD_PARAMETER, nil
+	}
+	if len(params.HashLock) > 0 && (len(params.HashLock) != sha256.Size || params.CancelAfter == 0) {
+		return block.TrBAD_PARAMETER, nil
+	}
+	if params.CancelAfter != 0 && params.CancelAfter <= params.FinishAfter {
+		return block.TrBAD_PARAMETER, nil
+	}
+	if params.CancelAfter != 0 && params.CancelAfter <= ctx.Timestamp {
+		return block.TrEXPIRED, nil
+	}
+	amount := tx.Amount
+	if !amount.IsPositive() {
+		return block.TrBAD_AMOUNT, nil
+	}
+	if !amount.IsNative() {
+		currency := ctx.GetCurrencyState(amount.Currency, amount.Issuer)
+		if currency == nil {
+			return block.TrNO_ENTRY, nil
+		}
+		if !libcore.Equals(tx.Account, amount.Issuer) && isFrozen(ctx, currency, tx.Account) {
+			return block.TrFROZEN, nil
+		}
+	}
+
+	if ctx.GetEscrowState(tx.Account, tx.Sequence, ctx.operation) != nil {
+		return block.TrALREADY, nil
+	}
+
+	from := ctx.GetAccountState(tx.Account, amount.Currency, amount.Issuer)
+	if from == nil || from.Amount.Less(amount) {
+		return block.TrBAD_AMOUNT, nil
+	}
+	debited, err := from.Amount.Subtract(amount)
+	if err != nil {
+		return block.TrBAD_AMOUNT, nil
+	}
+	from.Amount = *debited
+	ctx.PutState(from)
+	ctx.PutState(&block.EscrowState{
+		State: block.State{
+			Account:   tx.Account,
+			Sequence:  tx.Sequence,
+			StateType: block.ESCROW_STATE,
+		},
+		Destination: tx.Destination,
+		Amount:      amount,
+		FinishAfter: params.FinishAfter,
+		CancelAfter: params.CancelAfter,
+		HashLock:    params.HashLock,
+		Operation:   ctx.operation,
+	})
+	return block.TrSUCCESS, nil
+}
+
+// escrowAccounts returns the accounts of a transaction closing the escrow of
+// its EscrowReference payload. The transaction repeats the destination and
+// the amount of the escrow, so its accounts are known before it is applied.
+func escrowAccounts(tx *block.Transaction) []libcore.Address {
+	accounts := []libcore.Address{tx.Account, tx.Destination, tx.Amount.Issuer}
+	ref := &block.EscrowReference{}
+	if ref.UnmarshalBinary(tx.Payload) == nil {
+		accounts = append(accounts, ref.Owner)
+	}
+	return accounts
+}
+
+// openEscrow returns the open escrow of the EscrowReference payload of the
+// transaction, whose destination and amount must be the ones of the
+// transaction, with the payload.
+func openEscrow(ctx *Context, tx *block.Transaction) (*block.EscrowState, *block.EscrowReference, libblock.TransactionResult) {
+	ref := &block.EscrowReference{}
+	err := ref.UnmarshalBinary(tx.Payload)
+	if err != nil {
+		return nil, nil, block.TrBAD_PARAMETER
+	}
+	escrow := ctx.GetEscrowState(ref.Owner, ref.Sequence, ref.Operation)
+	if escrow == nil || escrow.Flags&block.ESCROW_CLOSED != 0 {
+		return nil, nil, block.TrNO_ENTRY
+	}
+	if !libcore.Equals(escrow.Destination, tx.Destination) || escrow.Amount.String() != tx.Amount.String() {
+		return nil, nil, block.TrBAD_PARAMETER
+	}
+	return escrow, ref, block.TrSUCCESS
+}
+
+// escrowFinish sends the amount of an escrow to its destination, from its
+// time to finish until its time to cancel. An escrow with a hash lock needs
+// the preimage of the hash, which is recorded in the receipt. Any account may
+// finish it.
+type escrowFinish struct{}
+
+func (h *escrowFinish) Accounts(tx *block.Transaction) []libcore.Address {
+	return escrowAccounts(tx)
+}
+
+func (h *escrowFinish) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	escrow, ref, result := openEscrow(ctx, tx)
+	if result != block.TrSUCCESS {
+		return result, nil
+	}
+	if ctx.Timestamp < escrow.FinishAfter {
+		return block.TrNOT_READY, nil
+	}
+	if escrow.CancelAfter != 0 && ctx.Timestamp >= escrow.CancelAfter {
+		return block.TrEXPIRED, nil
+	}
+	if len(escrow.HashLock) > 0 {
+		hash, err := ctx.GetCrypto().Hash(ref.Preimage)
+		if err != nil {
+			return block.TrBAD_SECRET, nil
+		}
+		if !bytes.Equal(hash, escrow.HashLock) {
+			return block.TrBAD_SECRET, nil
+		}
+	}
+
+	amount := escrow.Amount
+	to := ctx.GetAccountState(escrow.Destination, amount.Currency, amount.Issuer)
+	if to == nil {
+		to = NewAccountState(escrow.Destination, amount.Currency, amount.Issuer)
+	}
+	result = checkTransfer(ctx, escrow.Account, to, amount)
+	if result != block.TrSUCCESS {
+		return result, nil
+	}
+	escrow.Flags |= block.ESCROW_FINISHED
+	ctx.PutState(escrow)
+	result, err := credit(ctx, escrow.Destination, amount)
+	if result == block.TrSUCCESS && len(escrow.HashLock) > 0 {
+		ctx.GetReceipt().Preimage = ref.Preimage
+	}
+	return result, err
+}
+
+// escrowCancel sends the amount of an escrow back to its account, from its
+// time to cancel. Any account may cancel it.
+type escrowCancel struct{}
+
+func (h *escrowCancel) Accounts(tx *block.Transaction) []libcore.Address {
+	return escrowAccounts(tx)
+}
+
+func (h *escrowCancel) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	escrow, _, result := openEscrow(ctx, tx)
+	if result != block.TrSUCCESS {
+		return result, nil
+	}
+	if escrow.CancelAfter == 0 || ctx.Timestamp < escrow.CancelAfter {
+		return block.TrNOT_READY, nil
+	}
+
+	escrow.Flags |= block.ESCROW_CANCELLED
+	ctx.PutState(escrow)
+	return credit(ctx, escrow.Account, escrow.Amount)
+}
@@ -0,0 +1,262 @@
+package executor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/crypto"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+)
+
+func TestEscrow(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 3, 100)
+	owner, to, other := accounts[0], accounts[1], accounts[2]
+
+	create := func(v int64, finishAfter int64, cancelAfter int64) *block.Transaction {
+		payload, err := (&block.EscrowCreate{FinishAfter: finishAfter, CancelAfter: cancelAfter}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return owner.sign(t, &block.Transaction{
+			TransactionType: block.ESCROW_CREATE,
+			Amount:          *amount(t, v),
+			Destination:     to.address,
+			Payload:         payload,
+		})
+	}
+	close := func(from *testAccount, tt libblock.TransactionType, sequence uint64, v int64) *block.Transaction {
+		payload, err := (&block.EscrowReference{Owner: owner.address, Sequence: sequence}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return from.sign(t, &block.Transaction{
+			TransactionType: tt,
+			Amount:          *amount(t, v),
+			Destination:     to.address,
+			Payload:         payload,
+		})
+	}
+
+	e := NewExecutor(&crypto.CryptoService{}, 2)
+	txs := []libblock.Transaction{
+		create(10, 0, 0),
+		create(10, 200, 150),
+		create(10, 50, 100),
+		create(500, 200, 0),
+	}
+	vesting := create(30, 200, 300)
+	payroll := create(20, 150, 0)
+	expiring := create(10, 200, 300)
+	r := executeAt(t, e, base, Env{BlockIndex: 1, Timestamp: 100}, append(txs,
+		vesting,
+		payroll,
+		expiring,
+		close(other, block.ESCROW_FINISH, vesting.Sequence, 30),
+		close(other, block.ESCROW_CANCEL, vesting.Sequence, 30),
+	))
+	checkResults(t, r,
+		block.TrBAD_PARAMETER,
+		block.TrBAD_PARAMETER,
+		block.TrEXPIRED,
+		block.TrBAD_AMOUNT,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrNOT_READY,
+		block.TrNOT_READY,
+	)
+	checkBalance(t, r, owner, *amount(t, 0), 40)
+	err := r.Flush(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r = executeAt(t, e, base, Env{BlockIndex: 2, Timestamp: 250}, []libblock.Transaction{
+		close(other, block.ESCROW_FINISH, vesting.Sequence, 20),
+		close(other, block.ESCROW_FINISH, vesting.Sequence, 30),
+		close(other, block.ESCROW_FINISH, vesting.Sequence, 30),
+		close(other, block.ESCROW_CANCEL, payroll.Sequence, 20),
+		close(to, block.ESCROW_FINISH, payroll.Sequence, 20),
+	})
+	checkResults(t, r,
+		block.TrBAD_PARAMETER,
+		block.TrSUCCESS,
+		block.TrNO_ENTRY,
+		block.TrNOT_READY,
+		block.TrSUCCESS,
+	)
+	checkBalance(t, r, to, *amount(t, 0), 150)
+	err = r.Flush(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r = executeAt(t, e, base, Env{BlockIndex: 3, Timestamp: 300}, []libblock.Transaction{
+		close(other, block.ESCROW_FINISH, expiring.Sequence, 10),
+		close(other, block.ESCROW_CANCEL, expiring.Sequence, 10),
+		close(other, block.ESCROW_CANCEL, expiring.Sequence, 10),
+	})
+	checkResults(t, r, block.TrEXPIRED, block.TrSUCCESS, block.TrNO_ENTRY)
+	checkBalance(t, r, owner, *amount(t, 0), 50)
+}
+
+func TestHashLock(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 2, 100)
+	sender, counterparty := accounts[0], accounts[1]
+
+	secret := []byte("swap secret")
+	lock := sha256.Sum256(secret)
+	create := func(hashLock []byte, cancelAfter int64) *block.Transaction {
+		payload, err := (&block.EscrowCreate{CancelAfter: cancelAfter, HashLock: hashLock}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sender.sign(t, &block.Transaction{
+			TransactionType: block.ESCROW_CREATE,
+			Amount:          *amount(t, 40),
+			Destination:     counterparty.address,
+			Payload:         payload,
+		})
+	}
+	close := func(from *testAccount, tt libblock.TransactionType, sequence uint64, preimage []byte) *block.Transaction {
+		payload, err := (&block.EscrowReference{Owner: sender.address, Sequence: sequence, Preimage: preimage}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return from.sign(t, &block.Transaction{
+			TransactionType: tt,
+			Amount:          *amount(t, 40),
+			Destination:     counterparty.address,
+			Payload:         payload,
+		})
+	}
+
+	e := NewExecutor(&crypto.CryptoService{}, 2)
+	txs := []libblock.Transaction{
+		create(lock[:], 0),
+		create(lock[:4], 200),
+	}
+	claimed := create(lock[:], 200)
+	refunded := create(lock[:], 200)
+	r := executeAt(t, e, base, Env{BlockIndex: 1, Timestamp: 100}, append(txs, claimed, refunded))
+	checkResults(t, r, block.TrBAD_PARAMETER, block.TrBAD_PARAMETER, block.TrSUCCESS, block.TrSUCCESS)
+	err := r.Flush(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r = executeAt(t, e, base, Env{BlockIndex: 2, Timestamp: 150}, []libblock.Transaction{
+		close(counterparty, block.ESCROW_FINISH, claimed.Sequence, []byte("guess")),
+		close(sender, block.ESCROW_CANCEL, claimed.Sequence, nil),
+		close(counterparty, block.ESCROW_FINISH, claimed.Sequence, secret),
+	})
+	checkResults(t, r, block.TrBAD_SECRET, block.TrNOT_READY, block.TrSUCCESS)
+	checkBalance(t, r, counterparty, *amount(t, 0), 140)
+	if len(r.Transactions[0].GetReceipt().(*block.Receipt).GetPreimage()) != 0 {
+		t.Error("failed finish recorded a preimage")
+	}
+	data, err := r.Transactions[2].GetReceipt().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt := &block.Receipt{}
+	err = receipt.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(receipt.GetPreimage(), secret) {
+		t.Errorf("receipt has preimage %q, expected %q", receipt.GetPreimage(), secret)
+	}
+	err = r.Flush(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r = executeAt(t, e, base, Env{BlockIndex: 3, Timestamp: 200}, []libblock.Transaction{
+		close(counterparty, block.ESCROW_FINISH, refunded.Sequence, secret),
+		close(sender, block.ESCROW_CANCEL, refunded.Sequence, nil),
+	})
+	checkResults(t, r, block.TrEXPIRED, block.TrSUCCESS)
+	checkBalance(t, r, sender, *amount(t, 0), 60)
+}
+
+func TestCompoundEscrows(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 2, 100)
+	sender, counterparty := accounts[0], accounts[1]
+
+	batch := func(from *testAccount, ops ...*block.Transaction) *block.Transaction {
+		payload, err := (&block.Compound{Operations: ops}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return from.sign(t, &block.Transaction{
+			TransactionType: block.COMPOUND,
+			Payload:         payload,
+		})
+	}
+	secrets := [][]byte{[]byte("first secret"), []byte("second secret")}
+	create := func(secret []byte, v int64) *block.Transaction {
+		lock := sha256.Sum256(secret)
+		payload, err := (&block.EscrowCreate{CancelAfter: 200, HashLock: lock[:]}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &block.Transaction{
+			TransactionType: block.ESCROW_CREATE,
+			Amount:          *amount(t, v),
+			Destination:     counterparty.address,
+			Payload:         payload,
+		}
+	}
+	finish := func(sequence uint64, operation uint32, preimage []byte, v int64) *block.Transaction {
+		payload, err := (&block.EscrowReference{Owner: sender.address, Sequence: sequence, Operation: operation, Preimage: preimage}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &block.Transaction{
+			TransactionType: block.ESCROW_FINISH,
+			Amount:          *amount(t, v),
+			Destination:     counterparty.address,
+			Payload:         payload,
+		}
+	}
+
+	// the escrows of one compound transaction are told apart by operation
+	e := NewExecutor(&crypto.CryptoService{}, 2)
+	created := batch(sender, create(secrets[0], 40), create(secrets[1], 30))
+	r := executeAt(t, e, base, Env{BlockIndex: 1, Timestamp: 100}, []libblock.Transaction{created})
+	checkResults(t, r, block.TrSUCCESS)
+	checkBalance(t, r, sender, *amount(t, 0), 30)
+	err := r.Flush(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r = executeAt(t, e, base, Env{BlockIndex: 2, Timestamp: 150}, []libblock.Transaction{
+		batch(counterparty, finish(created.Sequence, 1, secrets[1], 30), finish(created.Sequence, 0, secrets[0], 40)),
+	})
+	checkResults(t, r, block.TrSUCCESS)
+	checkBalance(t, r, counterparty, *amount(t, 0), 170)
+	data, err := r.Transactions[0].GetReceipt().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt := &block.Receipt{}
+	err = receipt.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preimages := receipt.GetPreimages()
+	if len(preimages) != 2 || !bytes.Equal(preimages[0], secrets[1]) || !bytes.Equal(preimages[1], secrets[0]) {
+		t.Errorf("receipt has preimages %q", preimages)
+	}
+	if len(receipt.GetPreimage()) != 0 {
+		t.Errorf("receipt has preimage %q", receipt.GetPreimage())
+	}
+}
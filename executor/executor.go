@@ -0,0 +1,338 @@
+package executor
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/node"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+	libcrypto "github.com/tokentransfer/interfaces/crypto"
+)
+
+// Handler applies the transactions of a type.
+type Handler interface {
+	// Accounts returns the accounts whose states the transaction may read or
+	// write. Transactions with disjoint accounts may be applied concurrently.
+	Accounts(tx *block.Transaction) []libcore.Address
+
+	// Apply writes the changes of the transaction to the context. A result
+	// other than TrSUCCESS reverts the changes, while an error aborts the
+	// block.
+	Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error)
+}
+
+var handlers = map[libblock.TransactionType]Handler{}
+
+// Register sets the handler of the transaction type.
+func Register(t libblock.TransactionType, h Handler) {
+	handlers[t] = h
+}
+
+func getHandler(tx libblock.Transaction) (*block.Transaction, Handler) {
+	t, ok := tx.(*block.Transaction)
+	if !ok {
+		return nil, nil
+	}
+	h, ok := handlers[t.TransactionType]
+	if !ok {
+		return nil, nil
+	}
+	return t, h
+}
+
+// Executor applies the transactions of a block to the states of a
+// StateReader.
+type Executor struct {
+	crypto  libcrypto.CryptoService
+	workers int
+	fees    *FeeSchedule
+}
+
+// NewExecutor returns an executor with the number of workers, or one per CPU
+// if workers is 0.
+func NewExecutor(cs libcrypto.CryptoService, workers int) *Executor {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &Executor{
+		crypto:  cs,
+		workers: workers,
+	}
+}
+
+// SetFeeSchedule sets the fees charged to the transactions, or none if f is
+// nil.
+func (e *Executor) SetFeeSchedule(f *FeeSchedule) {
+	e.fees = f
+}
+
+// Result holds the transactions of an executed block with their receipts,
+// and the states they changed. Transactions which fail before their fee is
+// charged, such as those with a bad signature or sequence or too little gas,
+// are left out of the block.
+type Result struct {
+	Transactions []libblock.TransactionWithData
+	Dropped      []libblock.Transaction       // left out of the block
+	Results      []libblock.TransactionResult // of every executed transaction, in order
+
+	overlay *node.Overlay
+}
+
+// GetStates returns the final value of the changed states, in the order of
+// their first change.
+func (r *Result) GetStates() []libblock.State {
+	return r.overlay.Dirty()
+}
+
+// Flush puts the changed states to w.
+func (r *Result) Flush(w node.StateWriter) error {
+	return r.overlay.Flush(w)
+}
+
+type outcome struct {
+	result  libblock.TransactionResult
+	receipt *block.Receipt // recorded by the handler, nil if the fee wasn't charged
+	fee     int64          // charged to the account of the transaction
+	burned  int64          // part of the fee which isn't collected
+	states  []libblock.State
+	err     error
+}
+
+// ExecuteSequential applies the transactions one after the other.
+func (e *Executor) ExecuteSequential(base node.StateReader, env Env, txs []libblock.Transaction) (*Result, error) {
+	o := node.NewOverlay(base)
+	ctx := e.newContext(o, env)
+	outcomes := make([]*outcome, len(txs))
+	for i, tx := range txs {
+		outcomes[i] = e.apply(ctx, tx)
+		if outcomes[i].err != nil {
+			return nil, outcomes[i].err
+		}
+	}
+	err := e.collectFees(ctx, outcomes)
+	if err != nil {
+		return nil, err
+	}
+	return e.result(o, env, txs, outcomes), nil
+}
+
+// Execute applies the transactions on a pool of workers. The transactions
+// are split into groups which share no account, the groups are applied to
+// separate overlays, and their changes are merged in the order of the
+// transactions, so the result is the same as ExecuteSequential.
+func (e *Executor) Execute(base node.StateReader, env Env, txs []libblock.Transaction) (*Result, error) {
+	groups := schedule(txs)
+	if len(groups) <= 1 || e.workers == 1 {
+		return e.ExecuteSequential(base, env, txs)
+	}
+
+	reader := &lockedReader{base: base}
+	outcomes := make([]*outcome, len(txs))
+	queue := make(chan []int)
+	var wg sync.WaitGroup
+	for w := 0; w < e.workers && w < len(groups); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range queue {
+				ctx := e.newContext(node.NewOverlay(reader), env)
+				for _, i := range group {
+					outcomes[i] = e.apply(ctx, txs[i])
+					if outcomes[i].err != nil {
+						break
+					}
+				}
+			}
+		}()
+	}
+	for _, group := range groups {
+		queue <- group
+	}
+	close(queue)
+	wg.Wait()
+
+	o := node.NewOverlay(base)
+	for _, out := range outcomes {
+		if out == nil {
+			continue
+		}
+		if out.err != nil {
+			return nil, out.err
+		}
+		for _, state := range out.states {
+			o.PutState(state)
+		}
+	}
+	err := e.collectFees(e.newContext(o, env), outcomes)
+	if err != nil {
+		return nil, err
+	}
+	return e.result(o, env, txs, outcomes), nil
+}
+
+func (e *Executor) newContext(o *node.Overlay, env Env) *Context {
+	return &Context{
+		Env: env,
+
+		crypto:  e.crypto,
+		overlay: o,
+	}
+}
+
+// apply checks the signatures and sequence of the transaction and charges its
+// fee, then applies it with its handler. The sequence of the account is used
+// and the fee is charged even if the handler fails. A state which can't be
+// read fails the block rather than the transaction.
+func (e *Executor) apply(ctx *Context, tx libblock.Transaction) *outcome {
+	t, h := getHandler(tx)
+	if h == nil {
+		return &outcome{result: block.TrBAD_TRANSACTION}
+	}
+	authorized := e.authorize(ctx, t)
+	native := ctx.GetAccountState(t.Account, nil, nil)
+	if ctx.err != nil {
+		return &outcome{err: ctx.err}
+	}
+	if !authorized {
+		return &outcome{result: block.TrBAD_SIGNATURE}
+	}
+	if native == nil {
+		return &outcome{result: block.TrNO_ACCOUNT}
+	}
+	if t.Sequence != native.Sequence+1 {
+		return &outcome{result: block.TrBAD_SEQUENCE}
+	}
+
+	fee := e.fees.Fee(t) + ctx.BaseFee
+	result := chargeFee(native, t, fee)
+	if result != block.TrSUCCESS {
+		return &outcome{result: result}
+	}
+
+	start := ctx.overlay.Snapshot()
+	native.Sequence = t.Sequence
+	ctx.PutState(native)
+
+	id := ctx.overlay.Snapshot()
+	ctx.receipt = &block.Receipt{}
+	result, err := h.Apply(ctx, t)
+	if err == nil {
+		err = ctx.err
+	}
+	if err != nil {
+		return &outcome{err: err}
+	}
+	if result != block.TrSUCCESS {
+		ctx.overlay.RevertToSnapshot(id)
+	}
+	return &outcome{
+		result:  result,
+		receipt: ctx.receipt,
+		fee:     fee,
+		burned:  ctx.BaseFee,
+		states:  ctx.overlay.ChangedSince(start),
+	}
+}
+
+func (e *Executor) result(o *node.Overlay, env Env, txs []libblock.Transaction, outcomes []*outcome) *Result {
+	r := &Result{
+		Transactions: make([]libblock.TransactionWithData, 0, len(txs)),
+		Results:      make([]libblock.TransactionResult, len(txs)),
+		overlay:      o,
+	}
+	for i, tx := range txs {
+		r.Results[i] = outcomes[i].result
+		receipt := outcomes[i].receipt
+		if receipt == nil {
+			r.Dropped = append(r.Dropped, tx)
+			continue
+		}
+		receipt.BlockIndex = env.BlockIndex
+		receipt.TransactionIndex = uint32(len(r.Transactions))
+		receipt.TransactionResult = outcomes[i].result
+		receipt.Fee = outcomes[i].fee
+		receipt.States = outcomes[i].states
+		r.Transactions = append(r.Transactions, &block.TransactionWithData{
+			Transaction: tx,
+			Receipt:     receipt,
+			Date:        env.Timestamp,
+		})
+	}
+	return r
+}
+
+// schedule splits the transactions into groups which share no account,
+// including the accounts of their signers. The transactions of a group keep
+// their order.
+func schedule(txs []libblock.Transaction) [][]int {
+	parent := make([]int, len(txs))
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	owners := make(map[string]int)
+	for i, tx := range txs {
+		parent[i] = i
+		t, h := getHandler(tx)
+		if h == nil {
+			continue
+		}
+		// the signers' accounts are read to check their keys
+		accounts := h.Accounts(t)
+		for _, s := range t.Signers {
+			accounts = append(accounts, s.Account)
+		}
+		for _, a := range accounts {
+			if a == nil {
+				continue
+			}
+			key := a.String()
+			owner, ok := owners[key]
+			if !ok {
+				owners[key] = i
+				continue
+			}
+			ri, ro := find(i), find(owner)
+			if ri < ro {
+				parent[ro] = ri
+			} else if ro < ri {
+				parent[ri] = ro
+			}
+		}
+	}
+
+	groups := make([][]int, 0)
+	index := make(map[int]int)
+	for i := range txs {
+		r := find(i)
+		g, ok := index[r]
+		if !ok {
+			g = len(groups)
+			index[r] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// lockedReader serializes the reads of the workers from the base, as the
+// tries of MerkleService can't be read concurrently.
+type lockedReader struct {
+	lock sync.Mutex
+	base node.StateReader
+}
+
+func (r *lockedReader) GetStateByTypeAndKey(stateType libblock.StateType, stateKey string, s ...interface{}) (libblock.State, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.base.GetStateByTypeAndKey(stateType, stateKey, s...)
+}
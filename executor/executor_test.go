@@ -0,0 +1,445 @@
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tokentransfer/chain/account/jingtum"
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/crypto"
+	"github.com/tokentransfer/chain/node"
+	"github.com/tokentransfer/chain/pool"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+type stateKey struct {
+	stateType libblock.StateType
+	key       string
+}
+
+// mapStates is a StateReader over a map.
+type mapStates map[stateKey]libblock.State
+
+func (m mapStates) GetStateByTypeAndKey(stateType libblock.StateType, key string, s ...interface{}) (libblock.State, error) {
+	state, ok := m[stateKey{stateType, key}]
+	if !ok {
+		return nil, node.ErrorOfNonexists("state", key)
+	}
+	return state, nil
+}
+
+func (m mapStates) PutState(state libblock.State, s ...interface{}) error {
+	m[stateKey{state.GetStateType(), state.GetStateKey()}] = state
+	return nil
+}
+
+type testAccount struct {
+	key      *jingtum.Key
+	address  libcore.Address
+	sequence uint64
+}
+
+func newTestAccounts(t *testing.T, base mapStates, n int, balance int64) []*testAccount {
+	accounts := make([]*testAccount, n)
+	for i := 0; i < n; i++ {
+		key, err := jingtum.GenerateFamilySeed(fmt.Sprintf("account%d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		address, err := key.GetAddress()
+		if err != nil {
+			t.Fatal(err)
+		}
+		accounts[i] = &testAccount{key: key, address: address}
+
+		if balance > 0 {
+			state := NewAccountState(address, nil, nil)
+			state.Amount = *amount(t, balance)
+			base.PutState(state)
+		}
+	}
+	return accounts
+}
+
+func amount(t *testing.T, v int64) *core.Amount {
+	a, err := core.NewAmount(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func (a *testAccount) sign(t *testing.T, tx *block.Transaction) *block.Transaction {
+	a.sequence++
+	tx.Account = a.address
+	tx.Sequence = a.sequence
+	err := (&crypto.CryptoService{}).Sign(a.key, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func (a *testAccount) pay(t *testing.T, to *testAccount, v int64) *block.Transaction {
+	return a.sign(t, &block.Transaction{
+		TransactionType: block.TRANSACTION,
+		Amount:          *amount(t, v),
+		Destination:     to.address,
+	})
+}
+
+func encodeResult(t *testing.T, r *Result) [][]byte {
+	list := make([][]byte, 0)
+	for _, tx := range r.Transactions {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		list = append(list, data)
+	}
+	for _, state := range r.GetStates() {
+		data, err := state.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		list = append(list, data)
+	}
+	return list
+}
+
+func TestParallelExecution(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 12, 1000)
+	outsider := newTestAccounts(t, base, 13, 0)[12]
+
+	txs := make([]libblock.Transaction, 0)
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 12; i += 3 {
+			txs = append(txs, accounts[i].pay(t, accounts[i+1], int64(10+round)))
+			txs = append(txs, accounts[i+1].pay(t, accounts[i+2], int64(20+round)))
+		}
+	}
+	// an overdraft, a new account and a replayed sequence
+	txs = append(txs, accounts[0].pay(t, accounts[1], 5000))
+	txs = append(txs, accounts[3].pay(t, outsider, 100))
+	accounts[6].sequence--
+	txs = append(txs, accounts[6].pay(t, accounts[7], 1))
+
+	e := NewExecutor(&crypto.CryptoService{}, 4)
+	sequential, err := e.ExecuteSequential(base, Env{BlockIndex: 1}, txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := e.Execute(base, Env{BlockIndex: 1}, txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := encodeResult(t, sequential)
+	actual := encodeResult(t, parallel)
+	if len(expected) != len(actual) {
+		t.Fatalf("parallel execution has %d entries, expected %d", len(actual), len(expected))
+	}
+	for i := range expected {
+		if !bytes.Equal(expected[i], actual[i]) {
+			t.Fatalf("parallel execution differs at entry %d", i)
+		}
+	}
+
+	results := []libblock.TransactionResult{block.TrBAD_AMOUNT, block.TrSUCCESS, block.TrBAD_SEQUENCE}
+	l := len(txs) - len(results)
+	for i, result := range results {
+		actual := sequential.Results[l+i]
+		if actual != result {
+			t.Errorf("transaction %d has result %d, expected %d", l+i, actual, result)
+		}
+	}
+}
+
+// failingStates fails to read the states of an account.
+type failingStates struct {
+	mapStates
+	account libcore.Address
+}
+
+func (f *failingStates) GetStateByTypeAndKey(stateType libblock.StateType, key string, s ...interface{}) (libblock.State, error) {
+	if strings.HasPrefix(key, f.account.String()) {
+		return nil, errors.New("disk failure")
+	}
+	return f.mapStates.GetStateByTypeAndKey(stateType, key, s...)
+}
+
+func TestReadFailure(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 3, 100)
+	failing := &failingStates{base, accounts[2].address}
+
+	// a state which can't be read fails the block, rather than being missing
+	e := NewExecutor(&crypto.CryptoService{}, 2)
+	for _, tx := range []*block.Transaction{
+		accounts[2].pay(t, accounts[0], 10),
+		accounts[0].pay(t, accounts[2], 10),
+	} {
+		txs := []libblock.Transaction{accounts[1].pay(t, accounts[0], 10), tx}
+		if _, err := e.ExecuteSequential(failing, Env{BlockIndex: 1}, txs); err == nil {
+			t.Error("sequential execution ignored the read failure")
+		}
+		if _, err := e.Execute(failing, Env{BlockIndex: 1}, txs); err == nil {
+			t.Error("parallel execution ignored the read failure")
+		}
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 6, 0)
+	txs := []libblock.Transaction{
+		accounts[0].pay(t, accounts[1], 1),
+		accounts[2].pay(t, accounts[3], 1),
+		accounts[1].pay(t, accounts[4], 1),
+		accounts[3].pay(t, accounts[2], 1),
+		// shares the account of its signer
+		multiSign(t, accounts[5], 1, &block.Transaction{
+			TransactionType: block.TRANSACTION,
+			Amount:          *amount(t, 1),
+			Destination:     accounts[5].address,
+		}, accounts[3]),
+	}
+	groups := schedule(txs)
+	if fmt.Sprint(groups) != "[[0 2] [1 3 4]]" {
+		t.Errorf("groups %v", groups)
+	}
+}
+
+func TestFees(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 4, 100)
+	collector := newTestAccounts(t, base, 5, 0)[4]
+
+	pay := func(from *testAccount, to *testAccount, v int64, gas int64, payload []byte) *block.Transaction {
+		return from.sign(t, &block.Transaction{
+			TransactionType: block.TRANSACTION,
+			Amount:          *amount(t, v),
+			Gas:             gas,
+			Destination:     to.address,
+			Payload:         payload,
+		})
+	}
+	txs := []libblock.Transaction{
+		pay(accounts[0], accounts[1], 10, 10, nil),
+		pay(accounts[2], accounts[3], 10, 20, []byte{1, 2, 3}),
+		pay(accounts[1], accounts[0], 500, 10, nil),
+		pay(accounts[3], accounts[2], 10, 9, nil),
+	}
+
+	e := NewExecutor(&crypto.CryptoService{}, 4)
+	e.SetFeeSchedule(&FeeSchedule{
+		BaseFee: 10,
+		ByteFee: 2,
+		Account: collector.address,
+	})
+	sequential, err := e.ExecuteSequential(base, Env{BlockIndex: 1}, txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := e.Execute(base, Env{BlockIndex: 1}, txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(bytes.Join(encodeResult(t, sequential), nil), bytes.Join(encodeResult(t, parallel), nil)) {
+		t.Fatal("parallel execution differs")
+	}
+
+	// a failed payment pays its fee, a transaction with too little gas is
+	// left out of the block
+	expected := []struct {
+		result libblock.TransactionResult
+		fee    int64
+	}{
+		{block.TrSUCCESS, 10},
+		{block.TrSUCCESS, 16},
+		{block.TrBAD_AMOUNT, 10},
+	}
+	if len(sequential.Transactions) != len(expected) {
+		t.Fatalf("block has %d transactions, expected %d", len(sequential.Transactions), len(expected))
+	}
+	if len(sequential.Dropped) != 1 || sequential.Dropped[0] != txs[3] || sequential.Results[3] != block.TrINSUFF_GAS {
+		t.Errorf("dropped %d transactions, the last with result %d", len(sequential.Dropped), sequential.Results[3])
+	}
+	for i, e := range expected {
+		receipt := sequential.Transactions[i].GetReceipt().(*block.Receipt)
+		if receipt.TransactionResult != e.result || receipt.Fee != e.fee {
+			t.Errorf("transaction %d has result %d and fee %d, expected %d and %d", i, receipt.TransactionResult, receipt.Fee, e.result, e.fee)
+		}
+	}
+
+	balances := map[string]int64{
+		accounts[0].address.String(): 80,
+		accounts[1].address.String(): 100,
+		accounts[2].address.String(): 74,
+		accounts[3].address.String(): 110,
+		collector.address.String():   36,
+	}
+	for _, state := range sequential.GetStates() {
+		s := state.(*block.AccountState)
+		if v := s.Amount.Value.Value(); v != balances[s.Account.String()] {
+			t.Errorf("%s has balance %d, expected %d", s.Account, v, balances[s.Account.String()])
+		}
+	}
+	if len(sequential.GetStates()) != len(balances) {
+		t.Errorf("changed %d states, expected %d", len(sequential.GetStates()), len(balances))
+	}
+}
+
+func TestBaseFee(t *testing.T) {
+	s := &BaseFeeSchedule{Initial: 100, Min: 10, Target: 4}
+	withTransactions := func(fee int64, n int) *block.Block {
+		return &block.Block{
+			BaseFee:      fee,
+			Transactions: make([]libblock.TransactionWithData, n),
+		}
+	}
+	for _, c := range []struct {
+		parent *block.Block
+		next   int64
+	}{
+		{nil, 100},
+		{withTransactions(100, 4), 100},
+		{withTransactions(100, 8), 112},
+		{withTransactions(100, 0), 88},
+		{withTransactions(10, 0), 10},
+		{withTransactions(10, 5), 11},
+		{withTransactions(11, 5), 12},
+		{withTransactions(0, 5), 11},
+	} {
+		var parent libblock.Block
+		if c.parent != nil {
+			parent = c.parent
+		}
+		if next := s.Next(parent); next != c.next {
+			t.Errorf("next base fee %d, expected %d", next, c.next)
+		}
+	}
+}
+
+func TestFullness(t *testing.T) {
+	withFees := func(baseFee int64, fees ...int64) *block.Block {
+		b := &block.Block{BaseFee: baseFee}
+		for _, fee := range fees {
+			b.Transactions = append(b.Transactions, &block.TransactionWithData{Receipt: &block.Receipt{Fee: fee}})
+		}
+		return b
+	}
+	s := &BaseFeeSchedule{ByGas: true}
+	// the base fee doesn't count
+	for _, b := range []*block.Block{withFees(0, 10, 20), withFees(100, 110, 120)} {
+		if fullness := s.Fullness(b); fullness != 30 {
+			t.Errorf("fullness %d with base fee %d, expected 30", fullness, b.BaseFee)
+		}
+	}
+	s.ByGas = false
+	if fullness := s.Fullness(withFees(100, 110, 120)); fullness != 2 {
+		t.Errorf("fullness %d, expected 2", fullness)
+	}
+}
+
+func TestAssembler(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 4, 100)
+	collector := newTestAccounts(t, base, 5, 0)[4]
+
+	pay := func(from *testAccount, to *testAccount, gas int64) *block.Transaction {
+		return from.sign(t, &block.Transaction{
+			TransactionType: block.TRANSACTION,
+			Amount:          *amount(t, 1),
+			Gas:             gas,
+			Destination:     to.address,
+		})
+	}
+	// the first sequence of the last account is used
+	used := *accounts[3]
+	state, err := base.GetStateByTypeAndKey(block.ACCOUNT_STATE, core.GetAccountKey(used.address, nil, nil, "-"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.(*block.AccountState).Sequence = 1
+	accounts[3].sequence = 1
+
+	e := NewExecutor(&crypto.CryptoService{}, 2)
+	e.SetFeeSchedule(&FeeSchedule{BaseFee: 5, Account: collector.address})
+	p := pool.NewPool(e.Authorizer(base), 0)
+	for _, tx := range []*block.Transaction{
+		pay(accounts[0], accounts[1], 20),
+		pay(accounts[1], accounts[2], 30),
+		pay(accounts[2], accounts[3], 40),
+		pay(&used, accounts[0], 15),
+		pay(accounts[3], accounts[0], 12),
+		pay(accounts[0], accounts[1], 10),
+	} {
+		err := p.Add(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a := NewAssembler(e, p, &BaseFeeSchedule{Initial: 10, Target: 2})
+	parent := &block.Block{BlockIndex: 1, BaseFee: 10, Transactions: make([]libblock.TransactionWithData, 4)}
+	b, result, err := a.Assemble(base, parent, 1600000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.BlockIndex != 2 || b.BaseFee != 11 {
+		t.Fatalf("block %d has base fee %d", b.BlockIndex, b.BaseFee)
+	}
+
+	// the transaction under the base fee is held back, and the ones with a
+	// used sequence or which can't pay the whole fee are left out
+	expected := []struct {
+		gas    int64
+		result libblock.TransactionResult
+		fee    int64
+	}{
+		{40, block.TrSUCCESS, 16},
+		{30, block.TrSUCCESS, 16},
+		{20, block.TrSUCCESS, 16},
+	}
+	if len(b.Transactions) != len(expected) {
+		t.Fatalf("block has %d transactions, expected %d", len(b.Transactions), len(expected))
+	}
+	for i, e := range expected {
+		tx := b.Transactions[i].GetTransaction().(*block.Transaction)
+		receipt := b.Transactions[i].GetReceipt().(*block.Receipt)
+		if tx.Gas != e.gas || receipt.TransactionResult != e.result || receipt.Fee != e.fee {
+			t.Errorf("transaction %d has gas %d, result %d and fee %d", i, tx.Gas, receipt.TransactionResult, receipt.Fee)
+		}
+	}
+	state, err = result.overlay.GetState(block.ACCOUNT_STATE, core.GetAccountKey(collector.address, nil, nil, "-"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := state.(*block.AccountState).Amount.Value.Value(); v != 15 {
+		t.Errorf("collected %d, expected 15", v)
+	}
+	// only the transaction with a used sequence can never be applied
+	if p.Len() != len(expected)+2 {
+		t.Errorf("%d transactions left in the pool, expected %d", p.Len(), len(expected)+2)
+	}
+
+	err = a.Remove(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Len() != 2 {
+		t.Errorf("%d transactions left in the pool, expected 2", p.Len())
+	}
+	p.SetBaseFee(10)
+	if n := len(p.Pending(0)); n != 2 {
+		t.Errorf("%d transactions pending after the base fee fell, expected 2", n)
+	}
+}
@@ -0,0 +1,114 @@
+package executor
+
+import (
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/core"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+// FeeSchedule sets the fee of the transactions, in the native currency. The
+// fee of a transaction is the base fee of its type plus ByteFee for every
+// byte of its payload. A compound transaction also pays the base fees of its
+// operations.
+type FeeSchedule struct {
+	BaseFee  int64                              // base fee of the types missing in BaseFees
+	BaseFees map[libblock.TransactionType]int64 // base fee by transaction type
+	ByteFee  int64                              // fee per byte of Payload
+
+	// Account receives the fees of a block; the fees are burned if it is nil.
+	Account libcore.Address
+}
+
+// FeeConfig is implemented by configs which charge transaction fees.
+type FeeConfig interface {
+	GetFeeSchedule() *FeeSchedule
+}
+
+// GetFeeSchedule returns the fee schedule selected by the config, or nil if
+// transactions are free.
+func GetFeeSchedule(c libcore.Config) *FeeSchedule {
+	if fc, ok := c.(FeeConfig); ok {
+		return fc.GetFeeSchedule()
+	}
+	return nil
+}
+
+// Fee returns the fee of the transaction.
+func (f *FeeSchedule) Fee(tx *block.Transaction) int64 {
+	if f == nil {
+		return 0
+	}
+	fee := f.baseFee(tx.TransactionType)
+	if tx.TransactionType == block.COMPOUND {
+		params := &block.Compound{}
+		if params.UnmarshalBinary(tx.Payload) == nil {
+			for _, op := range params.Operations {
+				fee += f.baseFee(op.TransactionType)
+			}
+		}
+	}
+	return fee + f.ByteFee*int64(len(tx.Payload))
+}
+
+func (f *FeeSchedule) baseFee(t libblock.TransactionType) int64 {
+	fee, ok := f.BaseFees[t]
+	if !ok {
+		return f.BaseFee
+	}
+	return fee
+}
+
+// chargeFee debits the fee from the native balance. The Gas of the
+// transaction is the highest fee its account agrees to pay.
+func chargeFee(native *block.AccountState, tx *block.Transaction, fee int64) libblock.TransactionResult {
+	if fee <= 0 {
+		return block.TrSUCCESS
+	}
+	if tx.Gas < fee {
+		return block.TrINSUFF_GAS
+	}
+	debited, err := native.Amount.Subtract(nativeAmount(fee))
+	if err != nil || debited.IsNegative() {
+		return block.TrINSUFF_GAS
+	}
+	native.Amount = *debited
+	return block.TrSUCCESS
+}
+
+// collectFees credits the fees of the block to the fee account, once the
+// transactions are applied, so the account doesn't conflict with every
+// transaction of the block.
+func (e *Executor) collectFees(ctx *Context, outcomes []*outcome) error {
+	if e.fees == nil || e.fees.Account == nil {
+		return nil
+	}
+	total := int64(0)
+	for _, out := range outcomes {
+		total += out.fee - out.burned
+	}
+	if total == 0 {
+		return nil
+	}
+	collector := ctx.GetAccountState(e.fees.Account, nil, nil)
+	if ctx.err != nil {
+		return ctx.err
+	}
+	if collector == nil {
+		collector = NewAccountState(e.fees.Account, nil, nil)
+	}
+	credited, err := collector.Amount.Add(nativeAmount(total))
+	if err != nil {
+		return err
+	}
+	collector.Amount = *credited
+	ctx.PutState(collector)
+	return nil
+}
+
+// nativeAmount returns the value in the native currency.
+func nativeAmount(v int64) core.Amount {
+	a, _ := core.NewAmount(v) // never fails for an int64
+	return *a
+}
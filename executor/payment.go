@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/core"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+func init() {
+	Register(block.TRANSACTION, &payment{})
+}
+
+// payment moves the amount of the transaction from its account to its
+// destination, creating the balance of the destination if needed. Issued
+// currencies move within the limits of trust lines and the controls of their
+// issuer.
+type payment struct{}
+
+func (p *payment) Accounts(tx *block.Transaction) []libcore.Address {
+	return []libcore.Address{tx.Account, tx.Destination, tx.Amount.Issuer}
+}
+
+func (p *payment) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	amount := tx.Amount
+	if !amount.IsPositive() {
+		return block.TrBAD_AMOUNT, nil
+	}
+	if tx.Destination == nil || libcore.Equals(tx.Destination, tx.Account) {
+		return block.TrBAD_PARAMETER, nil
+	}
+
+	from := ctx.GetAccountState(tx.Account, amount.Currency, amount.Issuer)
+	if from == nil || from.Amount.Less(amount) {
+		return block.TrBAD_AMOUNT, nil
+	}
+	to := ctx.GetAccountState(tx.Destination, amount.Currency, amount.Issuer)
+	if to == nil {
+		to = NewAccountState(tx.Destination, amount.Currency, amount.Issuer)
+	}
+	result := checkTransfer(ctx, tx.Account, to, amount)
+	if result != block.TrSUCCESS {
+		return result, nil
+	}
+
+	return transfer(ctx, from, to, amount)
+}
+
+// transfer moves the amount between two balances in its currency.
+func transfer(ctx *Context, from *block.AccountState, to *block.AccountState, amount core.Amount) (libblock.TransactionResult, error) {
+	debited, err := from.Amount.Subtract(amount)
+	if err != nil || debited.IsNegative() {
+		return block.TrBAD_AMOUNT, nil
+	}
+	credited, err := to.Amount.Add(amount)
+	if err != nil {
+		return block.TrBAD_AMOUNT, nil
+	}
+	from.Amount = *debited
+	to.Amount = *credited
+	ctx.PutState(from)
+	ctx.PutState(to)
+	return block.TrSUCCESS, nil
+}
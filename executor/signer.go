@@ -0,0 +1,162 @@
+package executor
+
+import (
+	"bytes"
+
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/crypto"
+	"github.com/tokentransfer/chain/node"
+	"github.com/tokentransfer/chain/pool"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+// MAX_SIGNERS is the largest number of entries of a signer list.
+const MAX_SIGNERS = 32
+
+func init() {
+	Register(block.SIGNER_LIST_SET, &signerListSet{})
+}
+
+// signerListSet sets the signer list of its account to its SignerListSet
+// payload. The signers must be distinct accounts other than the account, with
+// weights adding up to at least the quorum. The list can't be removed while
+// it is the only way to sign for the account.
+type signerListSet struct{}
+
+func (h *signerListSet) Accounts(tx *block.Transaction) []libcore.Address {
+	return []libcore.Address{tx.Account}
+}
+
+func (h *signerListSet) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	params := &block.SignerListSet{}
+	err := params.UnmarshalBinary(tx.Payload)
+	if err != nil || len(params.Signers) > MAX_SIGNERS {
+		return block.TrBAD_PARAMETER, nil
+	}
+	if params.Quorum == 0 && len(params.Signers) > 0 {
+		return block.TrBAD_PARAMETER, nil
+	}
+
+	total := uint64(0)
+	seen := make(map[string]bool)
+	for _, e := range params.Signers {
+		if e.Account == nil || e.Weight == 0 || libcore.Equals(e.Account, tx.Account) {
+			return block.TrBAD_PARAMETER, nil
+		}
+		key := e.Account.String()
+		if seen[key] {
+			return block.TrBAD_PARAMETER, nil
+		}
+		seen[key] = true
+		total += uint64(e.Weight)
+	}
+	if total < uint64(params.Quorum) {
+		return block.TrBAD_PARAMETER, nil
+	}
+	if params.Quorum == 0 {
+		native := ctx.GetAccountState(tx.Account, nil, nil)
+		if native.Flags&block.ACCOUNT_DISABLE_MASTER != 0 && len(native.RegularKey) == 0 {
+			return block.TrNO_KEY, nil
+		}
+	}
+
+	list := ctx.GetSignerListState(tx.Account)
+	if list == nil {
+		list = &block.SignerListState{
+			State: block.State{
+				Account:   tx.Account,
+				StateType: block.SIGNER_LIST_STATE,
+			},
+		}
+	}
+	list.Quorum = params.Quorum
+	list.Signers = params.Signers
+	ctx.PutState(list)
+	return block.TrSUCCESS, nil
+}
+
+// authorize checks the signature of the transaction, by the master or the
+// regular key of the account, or the signatures of a multi-signed
+// transaction, whose distinct signers of the signer list of the account must
+// have weights adding up to its quorum.
+func (e *Executor) authorize(ctx *Context, tx *block.Transaction) bool {
+	if len(tx.Signers) == 0 {
+		native := ctx.GetAccountState(tx.Account, nil, nil)
+		if native == nil || (len(native.RegularKey) == 0 && native.Flags&block.ACCOUNT_DISABLE_MASTER == 0) {
+			ok, err := e.crypto.Verify(tx)
+			return err == nil && ok
+		}
+		verifier, ok := e.crypto.(crypto.AccountVerifier)
+		if !ok {
+			return false
+		}
+		ok, err := verifier.VerifyAccount(tx, native.RegularKey, native.Flags&block.ACCOUNT_DISABLE_MASTER != 0)
+		return err == nil && ok
+	}
+
+	verifier, ok := e.crypto.(crypto.SignatureVerifier)
+	if !ok {
+		return false
+	}
+	list := ctx.GetSignerListState(tx.Account)
+	if list == nil || list.Quorum == 0 {
+		return false
+	}
+	data, err := tx.Raw(true)
+	if err != nil {
+		return false
+	}
+	weight := uint64(0)
+	seen := make(map[string]bool)
+	for _, s := range tx.Signers {
+		if s.Account == nil {
+			return false
+		}
+		owner, err := verifier.VerifySignature(data, s.PublicKey, s.Signature)
+		if err != nil || !signsFor(ctx, s, owner) {
+			return false
+		}
+		key := s.Account.String()
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+		weight += uint64(list.GetWeight(s.Account))
+	}
+	return weight >= uint64(list.Quorum)
+}
+
+// signsFor reports whether the key of the signer, whose master key is the
+// one of owner, may sign for its account: it is the regular key of the
+// account, or its master key if it isn't disabled.
+func signsFor(ctx *Context, s block.Signer, owner libcore.Address) bool {
+	native := ctx.GetAccountState(s.Account, nil, nil)
+	if native == nil {
+		return libcore.Equals(owner, s.Account)
+	}
+	if len(native.RegularKey) > 0 && bytes.Equal(s.PublicKey, native.RegularKey) {
+		return true
+	}
+	return native.Flags&block.ACCOUNT_DISABLE_MASTER == 0 && libcore.Equals(owner, s.Account)
+}
+
+// Authorizer returns the checks of apply for the keys of the transactions
+// given to the pool, against the states of the last block.
+func (e *Executor) Authorizer(states node.StateReader) pool.Authorizer {
+	return &authorizer{
+		executor: e,
+		states:   &lockedReader{base: states},
+	}
+}
+
+type authorizer struct {
+	executor *Executor
+	states   node.StateReader
+}
+
+func (a *authorizer) Authorize(tx *block.Transaction) bool {
+	ctx := a.executor.newContext(node.NewOverlay(a.states), Env{})
+	return a.executor.authorize(ctx, tx) && ctx.err == nil
+}
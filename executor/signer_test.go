@@ -0,0 +1,169 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/crypto"
+	"github.com/tokentransfer/chain/pool"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcrypto "github.com/tokentransfer/interfaces/crypto"
+)
+
+// multiSign signs the transaction of the account with the keys of the
+// signers, for their addresses.
+func multiSign(t *testing.T, account *testAccount, sequence uint64, tx *block.Transaction, signers ...*testAccount) *block.Transaction {
+	cs := &crypto.CryptoService{}
+	tx.Account = account.address
+	tx.Sequence = sequence
+	data, err := tx.Raw(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range signers {
+		publicKey, signature, err := cs.SignData(s.key, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.Signers = append(tx.Signers, block.Signer{Account: s.address, PublicKey: publicKey, Signature: signature})
+	}
+	_, _, err = cs.Raw(tx, libcrypto.RawIgnoreSigningFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestMultiSign(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 6, 100)
+	treasury, a, b, c, outsider, to := accounts[0], accounts[1], accounts[2], accounts[3], accounts[4], accounts[5]
+
+	setList := func(quorum uint32, entries ...block.SignerEntry) *block.Transaction {
+		payload, err := (&block.SignerListSet{Quorum: quorum, Signers: entries}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return treasury.sign(t, &block.Transaction{
+			TransactionType: block.SIGNER_LIST_SET,
+			Payload:         payload,
+		})
+	}
+	entry := func(account *testAccount, weight uint32) block.SignerEntry {
+		return block.SignerEntry{Account: account.address, Weight: weight}
+	}
+
+	e := NewExecutor(&crypto.CryptoService{}, 2)
+	r := execute(t, e, base, []libblock.Transaction{
+		setList(3, entry(a, 1), entry(treasury, 2)),
+		setList(3, entry(a, 1), entry(a, 2)),
+		setList(4, entry(a, 1), entry(b, 2)),
+		setList(3, entry(a, 1), entry(b, 1), entry(c, 2)),
+	})
+	checkResults(t, r, block.TrBAD_PARAMETER, block.TrBAD_PARAMETER, block.TrBAD_PARAMETER, block.TrSUCCESS)
+	err := r.Flush(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pay := func(v int64) *block.Transaction {
+		return &block.Transaction{
+			TransactionType: block.TRANSACTION,
+			Amount:          *amount(t, v),
+			Destination:     to.address,
+		}
+	}
+	next := treasury.sequence + 1
+	tampered := multiSign(t, treasury, next, pay(10), a, c)
+	tampered.Amount = *amount(t, 90)
+	txs := []libblock.Transaction{
+		multiSign(t, treasury, next, pay(10), a, b),
+		multiSign(t, treasury, next, pay(10), a, a, b),
+		multiSign(t, treasury, next, pay(10), outsider, c),
+		tampered,
+		multiSign(t, treasury, next, pay(10), c, a),
+		multiSign(t, a, a.sequence+1, pay(10), b, c),
+	}
+
+	p := pool.NewPool(e.Authorizer(base), 0)
+	if err := p.Add(txs[4]); err != nil {
+		t.Errorf("pool refused multi-signed transaction: %v", err)
+	}
+	if err := p.Add(tampered); err != pool.ErrBadSignature {
+		t.Errorf("pool added tampered transaction: %v", err)
+	}
+	// a replacement with more gas must be signed for the account too
+	forged := multiSign(t, treasury, next, &block.Transaction{
+		TransactionType: block.TRANSACTION,
+		Amount:          *amount(t, 90),
+		Gas:             100,
+		Destination:     outsider.address,
+	}, outsider, c)
+	if err := p.Add(forged); err != pool.ErrBadSignature {
+		t.Errorf("pool added forged transaction: %v", err)
+	}
+	if pending := p.Pending(0); len(pending) != 1 || pending[0] != txs[4] {
+		t.Error("pool replaced the multi-signed transaction")
+	}
+
+	r = execute(t, e, base, txs)
+	checkResults(t, r,
+		block.TrBAD_SIGNATURE,
+		block.TrBAD_SIGNATURE,
+		block.TrBAD_SIGNATURE,
+		block.TrBAD_SIGNATURE,
+		block.TrSUCCESS,
+		block.TrBAD_SIGNATURE,
+	)
+	checkBalance(t, r, to, *amount(t, 0), 110)
+	err = r.Flush(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a signer signs with its regular key once its master key is disabled
+	regularKey, err := outsider.key.GetPublic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := regularKey.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	setKey, err := (&block.SetRegularKey{PublicKey: data}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	disableMaster, err := (&block.FlagsChange{SetFlags: block.ACCOUNT_DISABLE_MASTER}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = execute(t, e, base, []libblock.Transaction{
+		c.sign(t, &block.Transaction{TransactionType: block.SET_REGULAR_KEY, Payload: setKey}),
+		c.sign(t, &block.Transaction{TransactionType: block.ACCOUNT_SET, Payload: disableMaster}),
+	})
+	checkResults(t, r, block.TrSUCCESS, block.TrSUCCESS)
+	err = r.Flush(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byRegular := &testAccount{key: outsider.key, address: c.address}
+	next++
+	txs = []libblock.Transaction{
+		multiSign(t, treasury, next, pay(10), a, c),
+		multiSign(t, treasury, next, pay(10), a, outsider),
+		multiSign(t, treasury, next, pay(10), a, byRegular),
+	}
+	p = pool.NewPool(e.Authorizer(base), 0)
+	if err := p.Add(txs[0]); err != pool.ErrBadSignature {
+		t.Errorf("pool added transaction signed by a disabled master key: %v", err)
+	}
+	if err := p.Add(txs[2]); err != nil {
+		t.Errorf("pool refused transaction signed by a regular key: %v", err)
+	}
+	r = execute(t, e, base, txs)
+	checkResults(t, r, block.TrBAD_SIGNATURE, block.TrBAD_SIGNATURE, block.TrSUCCESS)
+	checkBalance(t, r, to, *amount(t, 0), 120)
+}
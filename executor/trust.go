@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/core"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+func init() {
+	Register(block.TRUST_SET, &trustSet{})
+}
+
+// trustSet sets the limit of the trust line of its account to the amount of
+// the transaction, creating the trust line if needed. A limit below the
+// balance only stops the account from receiving more.
+type trustSet struct{}
+
+func (h *trustSet) Accounts(tx *block.Transaction) []libcore.Address {
+	return []libcore.Address{tx.Account, tx.Amount.Issuer}
+}
+
+func (h *trustSet) Apply(ctx *Context, tx *block.Transaction) (libblock.TransactionResult, error) {
+	limit := tx.Amount
+	if limit.IsNegative() || limit.IsNative() {
+		return block.TrBAD_AMOUNT, nil
+	}
+	if libcore.Equals(limit.Issuer, tx.Account) {
+		return block.TrBAD_ACCOUNT, nil
+	}
+	currency := ctx.GetCurrencyState(limit.Currency, limit.Issuer)
+	if currency == nil {
+		return block.TrNO_ENTRY, nil
+	}
+
+	line := ctx.GetTrustLineState(tx.Account, limit.Currency, limit.Issuer)
+	if line == nil {
+		line = &block.TrustLineState{
+			State: block.State{
+				Account:   tx.Account,
+				StateType: block.TRUST_LINE_STATE,
+			},
+		}
+		addTrustLine(ctx, currency)
+	}
+	line.Limit = limit
+	ctx.PutState(line)
+	return block.TrSUCCESS, nil
+}
+
+// checkTransfer returns the result of moving the amount from the account to
+// the balance, for the controls of the issuer and the trust line of the
+// balance. A native amount always moves. The issuer may send to a frozen
+// trust line, and a frozen balance may be sent back to the issuer; other
+// amounts need a currency and trust lines which aren't frozen, and a trust
+// line with room for the amount, authorized if the currency requires it.
+func checkTransfer(ctx *Context, from libcore.Address, to *block.AccountState, amount core.Amount) libblock.TransactionResult {
+	if amount.IsNative() {
+		return block.TrSUCCESS
+	}
+	currency := ctx.GetCurrencyState(amount.Currency, amount.Issuer)
+	if currency == nil {
+		return block.TrNO_ENTRY
+	}
+	fromIssuer := libcore.Equals(from, amount.Issuer)
+	toIssuer := libcore.Equals(to.Account, amount.Issuer)
+	if toIssuer {
+		return block.TrSUCCESS
+	}
+	if !fromIssuer && isFrozen(ctx, currency, from) {
+		return block.TrFROZEN
+	}
+
+	line := ctx.GetTrustLineState(to.Account, amount.Currency, amount.Issuer)
+	if line == nil {
+		return block.TrNO_TRUST
+	}
+	if !fromIssuer && line.Flags&block.TRUST_LINE_FROZEN != 0 {
+		return block.TrFROZEN
+	}
+	if currency.Flags&block.CURRENCY_REQUIRE_AUTH != 0 && line.Flags&block.TRUST_LINE_AUTHORIZED == 0 {
+		return block.TrNO_AUTH
+	}
+	balance, err := to.Amount.Add(amount)
+	if err != nil || line.Limit.Less(*balance) {
+		return block.TrNO_TRUST
+	}
+	return block.TrSUCCESS
+}
+
+// isFrozen returns whether the currency is frozen, or the trust line of the
+// account for it.
+func isFrozen(ctx *Context, currency *block.CurrencyState, account libcore.Address) bool {
+	if currency.Flags&block.CURRENCY_FROZEN != 0 {
+		return true
+	}
+	amount := currency.TotalSupply
+	line := ctx.GetTrustLineState(account, amount.Currency, amount.Issuer)
+	return line != nil && line.Flags&block.TRUST_LINE_FROZEN != 0
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/crypto"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+)
+
+func TestTrustLine(t *testing.T) {
+	base := mapStates{}
+	accounts := newTestAccounts(t, base, 3, 100)
+	issuer, alice, bob := accounts[0], accounts[1], accounts[2]
+
+	op := func(from *testAccount, tt libblock.TransactionType, to *testAccount, a core.Amount) *block.Transaction {
+		tx := &block.Transaction{
+			TransactionType: tt,
+			Amount:          a,
+		}
+		if to != nil {
+			tx.Destination = to.address
+		}
+		return from.sign(t, tx)
+	}
+	usd := func(v int64) core.Amount {
+		return issued(t, v, "USD", issuer)
+	}
+	txs := []libblock.Transaction{
+		issuer.issue(t, "USD", "1000", 0),
+		op(alice, block.TRUST_SET, nil, issued(t, 100, "EUR", issuer)),
+		op(issuer, block.TRUST_SET, nil, usd(100)),
+		op(issuer, block.TRANSACTION, alice, usd(10)),
+		op(alice, block.TRUST_SET, nil, usd(100)),
+		op(issuer, block.TRANSACTION, alice, usd(60)),
+		op(issuer, block.TRANSACTION, alice, usd(50)),
+		op(alice, block.TRANSACTION, bob, usd(10)),
+		op(bob, block.TRUST_SET, nil, usd(5)),
+		op(alice, block.TRANSACTION, bob, usd(10)),
+		op(alice, block.TRANSACTION, bob, usd(5)),
+		op(alice, block.TRUST_SET, nil, usd(0)),
+		op(alice, block.TRANSACTION, issuer, usd(20)),
+		op(issuer, block.TRANSACTION, alice, usd(1)),
+	}
+
+	r := execute(t, NewExecutor(&crypto.CryptoService{}, 2), base, txs)
+	checkResults(t, r,
+		block.TrSUCCESS,
+		block.TrNO_ENTRY,
+		block.TrBAD_ACCOUNT,
+		block.TrNO_TRUST,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrNO_TRUST,
+		block.TrNO_TRUST,
+		block.TrSUCCESS,
+		block.TrNO_TRUST,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrSUCCESS,
+		block.TrNO_TRUST,
+	)
+	checkBalance(t, r, alice, usd(0), 35)
+	checkBalance(t, r, bob, usd(0), 5)
+	checkBalance(t, r, issuer, usd(0), 960)
+}
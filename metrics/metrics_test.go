@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/tokentransfer/check"
+)
+
+type MetricsSuite struct{}
+
+func Test_Metrics(t *testing.T) {
+	s := Suite(&MetricsSuite{})
+	TestingRun(t, s)
+}
+
+func (suite *MetricsSuite) TestCounter(c *C) {
+	r := NewRegistry()
+	r.Counter("gets_total", "Gets.", "store", "block").Inc()
+	r.Counter("gets_total", "Gets.", "store", "block").Add(2)
+	r.Counter("gets_total", "Gets.", "store", "index").Inc()
+
+	c.Assert(r.Counter("gets_total", "Gets.", "store", "block").Value(), Equals, uint64(3))
+
+	buf := &bytes.Buffer{}
+	err := r.WriteText(buf)
+	c.Assert(err, IsNil)
+	c.Assert(buf.String(), Equals, strings.Join([]string{
+		"# HELP gets_total Gets.",
+		"# TYPE gets_total counter",
+		`gets_total{store="block"} 3`,
+		`gets_total{store="index"} 1`,
+		"",
+	}, "\n"))
+}
+
+func writeText(c *C, r *Registry) string {
+	buf := &bytes.Buffer{}
+	err := r.WriteText(buf)
+	c.Assert(err, IsNil)
+	return buf.String()
+}
+
+func assertLines(c *C, text string, lines ...string) {
+	for _, line := range lines {
+		if !strings.Contains(text, line+"\n") {
+			c.Fatalf("missing %q in\n%s", line, text)
+		}
+	}
+}
+
+func (suite *MetricsSuite) TestHistogram(c *C) {
+	r := NewRegistry()
+	h := r.Histogram("latency_seconds", "Latency.")
+	h.Observe(0.25)
+	h.Observe(0.5)
+	h.Observe(2)
+	r.GaugeFunc("size", "Size.", func() float64 {
+		return 1.5
+	})
+
+	assertLines(c, writeText(c, r),
+		"# TYPE latency_seconds histogram",
+		`latency_seconds_bucket{le="0.1"} 0`,
+		`latency_seconds_bucket{le="0.5"} 2`,
+		`latency_seconds_bucket{le="1"} 2`,
+		`latency_seconds_bucket{le="5"} 3`,
+		`latency_seconds_bucket{le="+Inf"} 3`,
+		"latency_seconds_sum 2.75",
+		"latency_seconds_count 3",
+		"# TYPE size gauge",
+		"size 1.5",
+	)
+}
+
+func (suite *MetricsSuite) TestWith(c *C) {
+	r := NewRegistry()
+	for _, service := range []string{"a", "b"} {
+		v := float64(len(service))
+		view := r.With("service", service)
+		view.Counter("gets_total", "Gets.", "store", "block").Inc()
+		view.GaugeFunc("size", "Size.", func() float64 {
+			return v
+		}, "store", "block")
+	}
+	r.With("service", "a").Counter("gets_total", "Gets.", "store", "block").Inc()
+
+	assertLines(c, writeText(c, r.With("service", "b")),
+		`gets_total{service="a",store="block"} 2`,
+		`gets_total{service="b",store="block"} 1`,
+		`size{service="a",store="block"} 1`,
+		`size{service="b",store="block"} 1`,
+	)
+}
+
+func (suite *MetricsSuite) TestLabelEscaping(c *C) {
+	r := NewRegistry()
+	r.Counter("gets_total", "Gets.", "service", "C:\\data \"é\"\n\x01").Inc()
+
+	assertLines(c, writeText(c, r), "gets_total{service=\"C:\\\\data \\\"é\\\"\\n\x01\"} 1")
+}
@@ -0,0 +1,252 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tokentransfer/interfaces/core"
+)
+
+const (
+	COUNTER   = "counter"
+	GAUGE     = "gauge"
+	HISTOGRAM = "histogram"
+)
+
+// MetricsConfig is implemented by configs which enable metrics. The services
+// opened with the config record their metrics in the returned registry.
+type MetricsConfig interface {
+	GetMetricsRegistry() *Registry
+}
+
+// GetRegistry returns the registry selected by the config, or nil if metrics
+// are disabled.
+func GetRegistry(c core.Config) *Registry {
+	if mc, ok := c.(MetricsConfig); ok {
+		return mc.GetMetricsRegistry()
+	}
+	return nil
+}
+
+type family struct {
+	name   string
+	help   string
+	kind   string
+	series map[string]interface{}
+}
+
+// Registry holds metric families by name. Each family has one series per set
+// of labels, given as key and value pairs.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+
+	base   *Registry // set on the views returned by With
+	labels []string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+	}
+}
+
+// With returns a view of the registry which adds the labels to every metric
+// created through it, so that several services can share a registry.
+func (r *Registry) With(labels ...string) *Registry {
+	base := r
+	if r.base != nil {
+		base = r.base
+	}
+	return &Registry{
+		base:   base,
+		labels: r.withLabels(labels),
+	}
+}
+
+func (r *Registry) get(name string, help string, kind string, labels []string, create func() interface{}) interface{} {
+	if r.base != nil {
+		return r.base.get(name, help, kind, r.withLabels(labels), create)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{
+			name:   name,
+			help:   help,
+			kind:   kind,
+			series: make(map[string]interface{}),
+		}
+		r.families[name] = f
+	}
+	if f.kind != kind {
+		panic(fmt.Sprintf("metric %s registered as %s", name, f.kind))
+	}
+	key := formatLabels(labels)
+	m, ok := f.series[key]
+	if !ok {
+		m = create()
+		f.series[key] = m
+	}
+	return m
+}
+
+// Counter returns the counter with the name and labels, creating it on the
+// first call.
+func (r *Registry) Counter(name string, help string, labels ...string) *Counter {
+	return r.get(name, help, COUNTER, labels, func() interface{} {
+		return &Counter{}
+	}).(*Counter)
+}
+
+func (r *Registry) Gauge(name string, help string, labels ...string) *Gauge {
+	return r.get(name, help, GAUGE, labels, func() interface{} {
+		return &Gauge{}
+	}).(*Gauge)
+}
+
+func (r *Registry) Histogram(name string, help string, labels ...string) *Histogram {
+	return r.get(name, help, HISTOGRAM, labels, func() interface{} {
+		return NewHistogram(DEFAULT_BUCKETS)
+	}).(*Histogram)
+}
+
+// GaugeFunc registers a gauge read from f, replacing any previous one with
+// the same name and labels.
+func (r *Registry) GaugeFunc(name string, help string, f GaugeFunc, labels ...string) {
+	if r.base != nil {
+		r.base.GaugeFunc(name, help, f, r.withLabels(labels)...)
+		return
+	}
+
+	r.get(name, help, GAUGE, labels, func() interface{} {
+		return f
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.families[name].series[formatLabels(labels)] = f
+}
+
+func (r *Registry) withLabels(labels []string) []string {
+	l := make([]string, 0, len(r.labels)+len(labels))
+	l = append(l, r.labels...)
+	return append(l, labels...)
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format. A
+// view writes the metrics of the whole registry.
+func (r *Registry) WriteText(w io.Writer) error {
+	if r.base != nil {
+		return r.base.WriteText(w)
+	}
+
+	r.mu.Lock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		series := make(map[string]interface{}, len(f.series))
+		for k, v := range f.series {
+			series[k] = v
+		}
+		families = append(families, &family{f.name, f.help, f.kind, series})
+	}
+	r.mu.Unlock()
+
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name < families[j].name
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.kind)
+
+		keys := make([]string, 0, len(f.series))
+		for k := range f.series {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			switch m := f.series[k].(type) {
+			case *Counter:
+				fmt.Fprintf(bw, "%s%s %d\n", f.name, wrapLabels(k), m.Value())
+			case *Gauge:
+				fmt.Fprintf(bw, "%s%s %s\n", f.name, wrapLabels(k), formatFloat(m.Value()))
+			case GaugeFunc:
+				fmt.Fprintf(bw, "%s%s %s\n", f.name, wrapLabels(k), formatFloat(m()))
+			case *Histogram:
+				counts, sum, count := m.Snapshot()
+				for i, b := range m.Buckets {
+					fmt.Fprintf(bw, "%s_bucket%s %d\n", f.name, wrapLabels(joinLabels(k, "le", formatFloat(b))), counts[i])
+				}
+				fmt.Fprintf(bw, "%s_bucket%s %d\n", f.name, wrapLabels(joinLabels(k, "le", "+Inf")), count)
+				fmt.Fprintf(bw, "%s_sum%s %s\n", f.name, wrapLabels(k), formatFloat(sum))
+				fmt.Fprintf(bw, "%s_count%s %d\n", f.name, wrapLabels(k), count)
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		err := r.WriteText(w)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+func formatLabels(labels []string) string {
+	if len(labels)%2 != 0 {
+		panic("labels must be key and value pairs")
+	}
+	list := make([]string, 0, len(labels)/2)
+	for i := 0; i < len(labels); i += 2 {
+		list = append(list, fmt.Sprintf(`%s="%s"`, labels[i], escapeLabel(labels[i+1])))
+	}
+	return strings.Join(list, ",")
+}
+
+func joinLabels(labels string, key string, value string) string {
+	l := formatLabels([]string{key, value})
+	if len(labels) == 0 {
+		return l
+	}
+	return labels + "," + l
+}
+
+func wrapLabels(labels string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// escapeLabel escapes a label value as the text format allows: only the
+// backslash, the double quote and the line feed.
+func escapeLabel(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+func escapeHelp(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
@@ -0,0 +1,409 @@
+package node
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/tokentransfer/chain/store"
+
+	libcore "github.com/tokentransfer/interfaces/core"
+	libcrypto "github.com/tokentransfer/interfaces/crypto"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+const (
+	BACKUP_VERSION  = 1
+	BACKUP_MANIFEST = "manifest.json"
+
+	restoreBatchSize = 1000
+)
+
+// Manifest describes the content of a backup archive.
+type Manifest struct {
+	Version   int               `json:"version"`
+	Created   int64             `json:"created"`
+	HeadIndex uint64            `json:"head_index"`
+	HeadHash  string            `json:"head_hash"`
+	Roots     map[string]string `json:"roots"`
+	Files     []ManifestFile    `json:"files"`
+}
+
+// ManifestFile describes one store of the backup, saved as a sequence of
+// entries written by store.WriteEntry.
+type ManifestFile struct {
+	Name     string `json:"name"`
+	File     string `json:"file"`
+	Entries  uint64 `json:"entries"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+func (service *MerkleService) trees() []*MerkleTree {
+	return []*MerkleTree{service.im, service.bm, service.tm, service.sm}
+}
+
+func treeNames() []string {
+	return []string{"index", "block", "transaction", "receipt"}
+}
+
+type countWriter struct {
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// Backup writes a gzip compressed tar archive of all stores to w while the
+// service keeps running. The stores are read from snapshots taken once the
+// last commit is written, so the archive matches the head and roots of that
+// commit in its manifest. The pending changes are left out.
+func (service *MerkleService) Backup(w io.Writer) (*Manifest, error) {
+	names := treeNames()
+	trees := service.trees()
+	snapshots := make([]store.Snapshot, 0, len(trees))
+	defer func() {
+		for _, snapshot := range snapshots {
+			snapshot.Release()
+		}
+	}()
+
+	manifest := &Manifest{
+		Version: BACKUP_VERSION,
+		Created: time.Now().Unix(),
+		Roots:   make(map[string]string),
+	}
+
+	service.lock.Lock()
+	// the snapshots must include the commits still in the pipeline, and the
+	// uncommitted changes are only in the tries
+	err := service.waitSeqs(service.sealed())
+	if err != nil {
+		service.lock.Unlock()
+		return nil, err
+	}
+	index, hash, err := service.getHead()
+	if err == nil {
+		manifest.HeadIndex = index
+		manifest.HeadHash = hex.EncodeToString(hash)
+	}
+	for i, t := range trees {
+		s, ok := store.Unwrap(t.ss).(store.Snapshotter)
+		if !ok {
+			service.lock.Unlock()
+			return nil, fmt.Errorf("store %s doesn't support snapshots", names[i])
+		}
+		snapshot, err := s.Snapshot()
+		if err != nil {
+			service.lock.Unlock()
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+		manifest.Roots[names[i]] = hex.EncodeToString(t.GetCommittedRoot())
+	}
+	service.lock.Unlock()
+
+	// the tar headers need the sizes, so the snapshots are read twice
+	for i, snapshot := range snapshots {
+		h := sha256.New()
+		cw := &countWriter{}
+		mw := io.MultiWriter(h, cw)
+		entries := uint64(0)
+		err := snapshot.ListData(func(key []byte, value []byte) error {
+			entries++
+			return store.WriteEntry(mw, key, value)
+		})
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, ManifestFile{
+			Name:     names[i],
+			File:     names[i] + ".kv",
+			Entries:  entries,
+			Size:     cw.n,
+			Checksum: hex.EncodeToString(h.Sum(nil)),
+		})
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	err = tw.WriteHeader(&tar.Header{
+		Name:    BACKUP_MANIFEST,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Unix(manifest.Created, 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = tw.Write(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, snapshot := range snapshots {
+		file := manifest.Files[i]
+		err = tw.WriteHeader(&tar.Header{
+			Name:    file.File,
+			Mode:    0644,
+			Size:    file.Size,
+			ModTime: time.Unix(manifest.Created, 0),
+		})
+		if err != nil {
+			return nil, err
+		}
+		bw := bufio.NewWriter(tw)
+		err = snapshot.ListData(func(key []byte, value []byte) error {
+			return store.WriteEntry(bw, key, value)
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = bw.Flush()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return nil, err
+	}
+	err = gw.Close()
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// BackupFile writes the backup archive to a new file.
+func (service *MerkleService) BackupFile(file string) (*Manifest, error) {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := service.Backup(f)
+	if err == nil {
+		err = f.Sync()
+	}
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file)
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// readBackup walks the archive, calling each with the manifest entry and the
+// content of every store file.
+func readBackup(file string, each func(manifest *Manifest, f ManifestFile, r io.Reader) error) (*Manifest, error) {
+	fd, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	gr, err := gzip.NewReader(bufio.NewReader(fd))
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+
+	header, err := tr.Next()
+	if err != nil {
+		return nil, err
+	}
+	if header.Name != BACKUP_MANIFEST {
+		return nil, ErrorOfNonexists("backup", BACKUP_MANIFEST)
+	}
+	data, err := io.ReadAll(tr)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{}
+	err = json.Unmarshal(data, manifest)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Version != BACKUP_VERSION {
+		return nil, fmt.Errorf("unsupported backup version: %d", manifest.Version)
+	}
+	if len(manifest.Files) != len(treeNames()) {
+		return nil, errors.New("error backup files")
+	}
+	for i, name := range treeNames() {
+		if manifest.Files[i].Name != name {
+			return nil, ErrorOfNonexists("backup", name)
+		}
+	}
+
+	for _, f := range manifest.Files {
+		header, err := tr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if header.Name != f.File {
+			return nil, ErrorOfNonexists("backup", f.File)
+		}
+		err = each(manifest, f, tr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return manifest, nil
+}
+
+// VerifyBackup checks the size and checksum of every file in the archive
+// against its manifest.
+func VerifyBackup(file string) (*Manifest, error) {
+	return readBackup(file, func(manifest *Manifest, f ManifestFile, r io.Reader) error {
+		h := sha256.New()
+		n, err := io.Copy(h, r)
+		if err != nil {
+			return err
+		}
+		if n != f.Size {
+			return ErrorOfInvalid("backup size", f.File)
+		}
+		if hex.EncodeToString(h.Sum(nil)) != f.Checksum {
+			return ErrorOfInvalid("backup checksum", f.File)
+		}
+		return nil
+	})
+}
+
+// RestoreBackup verifies the archive, then rebuilds the stores of the config
+// from it. The stores must be empty. The restored roots and head are checked
+// against the manifest.
+func RestoreBackup(file string, c libcore.Config, cs libcrypto.CryptoService) (*Manifest, error) {
+	_, err := VerifyBackup(file)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := store.GetBackend(c)
+	manifest, err := readBackup(file, func(manifest *Manifest, f ManifestFile, r io.Reader) error {
+		db, err := store.NewKvService(backend, f.Name, c)
+		if err != nil {
+			return err
+		}
+		err = restoreStore(db, f, r)
+		closeErr := db.Close()
+		if err != nil {
+			return err
+		}
+		return closeErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	service := &MerkleService{
+		config: c,
+		crypto: cs,
+	}
+	err = service.Init(c)
+	if err != nil {
+		return nil, err
+	}
+	defer service.Close()
+
+	err = service.checkManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+var errNotEmpty = errors.New("not empty")
+
+func restoreStore(db libstore.KvService, f ManifestFile, r io.Reader) error {
+	err := db.ListData(func(key []byte, value []byte) error {
+		return errNotEmpty
+	})
+	if err == errNotEmpty {
+		return fmt.Errorf("store %s is not empty", f.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(r)
+	keys := make([][]byte, 0, restoreBatchSize)
+	values := make([][]byte, 0, restoreBatchSize)
+	entries := uint64(0)
+	for {
+		key, value, err := store.ReadEntry(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		entries++
+		keys = append(keys, key)
+		values = append(values, value)
+		if len(keys) == restoreBatchSize {
+			err = db.PutDatas(keys, values)
+			if err != nil {
+				return err
+			}
+			keys = keys[:0]
+			values = values[:0]
+		}
+	}
+	if len(keys) > 0 {
+		err = db.PutDatas(keys, values)
+		if err != nil {
+			return err
+		}
+	}
+	if entries != f.Entries {
+		return ErrorOfInvalid("backup entries", f.File)
+	}
+	return db.Flush()
+}
+
+// checkManifest compares the roots and head of the service with a manifest.
+func (service *MerkleService) checkManifest(manifest *Manifest) error {
+	for i, t := range service.trees() {
+		name := treeNames()[i]
+		root, err := hex.DecodeString(manifest.Roots[name])
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(root, t.GetRoot()) {
+			return ErrorOfInvalid("root", name)
+		}
+	}
+	if len(manifest.HeadHash) > 0 {
+		index, hash, err := service.GetHead()
+		if err != nil {
+			return err
+		}
+		if index != manifest.HeadIndex || hex.EncodeToString(hash) != manifest.HeadHash {
+			return ErrorOfInvalid("head", fmt.Sprintf("%d", manifest.HeadIndex))
+		}
+	}
+	return nil
+}
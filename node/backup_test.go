@@ -0,0 +1,171 @@
+package node
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/tokentransfer/chain/crypto"
+	"github.com/tokentransfer/chain/store"
+
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+// backupService backs up a service with a committed and an uncommitted state
+// and returns the hash of the uncommitted one.
+// backupService returns a service with a committed state and a pending one,
+// with their hashes, and the file of its backup.
+func backupService(t *testing.T) (*MerkleService, libcore.Hash, libcore.Hash, string) {
+	service := newTestService(t, &testConfig{backend: store.SNAPSHOT})
+	alice := withValue(t, testAccountState(t, "alice"), "100")
+	err := service.PutState(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = service.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// left uncommitted, out of the backup
+	bob := withValue(t, testAccountState(t, "bob"), "50")
+	err = service.PutState(bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := path.Join(t.TempDir(), "backup.tar.gz")
+	_, err = service.BackupFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service, alice.GetHash(), bob.GetHash(), file
+}
+
+// rewriteBackup copies the archive with the manifest changed by f.
+func rewriteBackup(t *testing.T, file string, f func(manifest *Manifest)) string {
+	fd, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+	gr, err := gzip.NewReader(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.Name == BACKUP_MANIFEST {
+			manifest := &Manifest{}
+			err = json.Unmarshal(data, manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f(manifest)
+			data, err = json.Marshal(manifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			header.Size = int64(len(data))
+		}
+		err = tw.WriteHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = tw.Write(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = tw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = gw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := path.Join(t.TempDir(), "rewritten.tar.gz")
+	err = os.WriteFile(out, buf.Bytes(), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestBackupRestore(t *testing.T) {
+	service, committed, pending, file := backupService(t)
+
+	c := &testConfig{dir: t.TempDir(), backend: store.SNAPSHOT}
+	manifest, err := RestoreBackup(file, c, &crypto.CryptoService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := newTestService(t, c)
+	for i, name := range treeNames() {
+		root := hex.EncodeToString(restored.trees()[i].GetRoot())
+		if root != hex.EncodeToString(service.trees()[i].GetCommittedRoot()) {
+			t.Fatalf("%s root: got %s", name, root)
+		}
+		if root != manifest.Roots[name] {
+			t.Fatalf("%s root: got %s, manifest has %s", name, root, manifest.Roots[name])
+		}
+	}
+	_, err = restored.GetStateByHash(committed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = restored.GetStateByHash(pending)
+	if !IsNonexists(err) {
+		t.Fatalf("restored a pending state: %v", err)
+	}
+
+	// the backup doesn't commit the pending state
+	_, err = service.GetStateByHash(pending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(service.sm.GetRoot(), service.sm.GetCommittedRoot()) {
+		t.Fatal("the pending state was committed")
+	}
+}
+
+func TestRestoreCorruptManifest(t *testing.T) {
+	_, _, _, file := backupService(t)
+
+	wrongRoot := rewriteBackup(t, file, func(manifest *Manifest) {
+		manifest.Roots["receipt"] = manifest.Roots["index"]
+	})
+	_, err := RestoreBackup(wrongRoot, &testConfig{dir: t.TempDir(), backend: store.SNAPSHOT}, &crypto.CryptoService{})
+	if err == nil {
+		t.Fatal("restored a backup with a wrong root")
+	}
+
+	wrongChecksum := rewriteBackup(t, file, func(manifest *Manifest) {
+		manifest.Files[0].Checksum = manifest.Files[1].Checksum
+	})
+	_, err = VerifyBackup(wrongChecksum)
+	if err == nil {
+		t.Fatal("verified a backup with a wrong checksum")
+	}
+}
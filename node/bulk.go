@@ -0,0 +1,74 @@
+package node
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/tokentransfer/go-MerklePatriciaTree/mpt"
+
+	"github.com/tokentransfer/chain/store"
+)
+
+const (
+	bulkCommitSize = 10000    // keys inserted between two commits
+	bulkBatchSize  = 64 << 20 // bytes of nodes buffered before a write
+)
+
+var errUnsorted = errors.New("bulk load keys must be sorted and unique")
+
+// BulkLoad inserts the entries listed by list, which must be in strictly
+// ascending key order, e.g. the ListData of a store. The trie isn't built
+// bottom-up: the node types of the trie package can't be built from here, so
+// the entries go through the trie's own insert, and the gain over PutData
+// comes from writing the nodes in large batches rather than one write per
+// node. That is also why the root is the same as inserting the entries one by
+// one. The tree must have no uncommitted changes; the entries are committed
+// when BulkLoad returns. If it fails, the batches written so far are kept in
+// the store.
+func (t *MerkleTree) BulkLoad(list func(each func(key []byte, value []byte) error) error) error {
+	batch := store.NewBatchService(t.ss)
+	trie := mpt.New(t.cs, batch)
+
+	var previous []byte
+	count := 0
+	err := list(func(key []byte, value []byte) error {
+		if previous != nil && bytes.Compare(key, previous) <= 0 {
+			return errUnsorted
+		}
+		previous = append(previous[:0], key...)
+
+		err := trie.Put(key, value)
+		if err != nil {
+			return err
+		}
+		count++
+		if count%bulkCommitSize != 0 {
+			return nil
+		}
+		err = trie.Commit()
+		if err != nil {
+			return err
+		}
+		if batch.Size() < bulkBatchSize {
+			return nil
+		}
+		return batch.Flush()
+	})
+	if err != nil {
+		trie.Abort()
+		return err
+	}
+
+	err = trie.Commit()
+	if err != nil {
+		return err
+	}
+	err = batch.Flush()
+	if err != nil {
+		return err
+	}
+	// reload the root written by the bulk trie
+	t.mt = mpt.New(t.cs, &storeErrors{t.ss})
+	t.committed = t.mt.RootHash()
+	return nil
+}
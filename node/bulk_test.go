@@ -0,0 +1,105 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/tokentransfer/chain/crypto"
+	"github.com/tokentransfer/chain/store"
+)
+
+func newMemoryTree() *MerkleTree {
+	kv := &store.MemoryService{}
+	kv.Init(nil)
+	return NewMerkleTree(&crypto.CryptoService{}, kv)
+}
+
+func bulkEntries(n int) ([][]byte, [][]byte) {
+	keys := make([][]byte, n)
+	values := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		keys[i] = []byte(fmt.Sprintf("state@%08d", i))
+		values[i] = []byte(fmt.Sprintf("value-%d", i))
+	}
+	return keys, values
+}
+
+func listEntries(keys [][]byte, values [][]byte) func(each func(key []byte, value []byte) error) error {
+	return func(each func(key []byte, value []byte) error) error {
+		for i := range keys {
+			err := each(keys[i], values[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestBulkLoadRoot(t *testing.T) {
+	keys, values := bulkEntries(1000)
+
+	incremental := newMemoryTree()
+	for i := range keys {
+		err := incremental.PutData(keys[i], values[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := incremental.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bulk := newMemoryTree()
+	err = bulk.BulkLoad(listEntries(keys, values))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(incremental.GetRoot(), bulk.GetRoot()) {
+		t.Error("bulk load root differs from incremental root")
+	}
+	value, err := bulk.GetData(keys[500])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value, values[500]) {
+		t.Error("bulk load value wrong")
+	}
+
+	err = bulk.BulkLoad(listEntries([][]byte{keys[1], keys[0]}, [][]byte{values[1], values[0]}))
+	if err != errUnsorted {
+		t.Error("unsorted keys accepted")
+	}
+}
+
+func BenchmarkIncrementalInsert(b *testing.B) {
+	keys, values := bulkEntries(10000)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tree := newMemoryTree()
+		for i := range keys {
+			err := tree.PutData(keys[i], values[i])
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+		err := tree.Commit()
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBulkLoad(b *testing.B) {
+	keys, values := bulkEntries(10000)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tree := newMemoryTree()
+		err := tree.BulkLoad(listEntries(keys, values))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/tokentransfer/chain/crypto"
+	"github.com/tokentransfer/chain/store"
+)
+
+func newCommitTrees(states int) []*MerkleTree {
+	trees := []*MerkleTree{newMemoryTree(), newMemoryTree(), newMemoryTree(), newMemoryTree()}
+	fillCommitTrees(trees, states)
+	return trees
+}
+
+// newNamespacedTrees opens the trees in one namespaced database.
+func newNamespacedTrees(t *testing.T, states int) []*MerkleTree {
+	c := &testConfig{dir: t.TempDir()}
+	trees := make([]*MerkleTree, 0)
+	for _, name := range treeNames() {
+		db, err := store.NewKvService(store.NAMESPACED, name, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		trees = append(trees, NewMerkleTree(&crypto.CryptoService{}, db))
+	}
+	fillCommitTrees(trees, states)
+	return trees
+}
+
+func fillCommitTrees(trees []*MerkleTree, states int) {
+	for i, t := range trees {
+		for j := 0; j < states; j++ {
+			key := []byte(fmt.Sprintf("tree%d@%d", i, j))
+			err := t.PutData(key, []byte(fmt.Sprintf("value-%d", j)))
+			if err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+func TestParallelCommitRoots(t *testing.T) {
+	sequential := newCommitTrees(100)
+	err := commitTrees(sequential, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel := newCommitTrees(100)
+	err = commitTrees(parallel, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range sequential {
+		if !bytes.Equal(sequential[i].GetRoot(), parallel[i].GetRoot()) {
+			t.Errorf("root of tree %d differs", i)
+		}
+	}
+}
+
+func TestParallelCommitNamespaced(t *testing.T) {
+	sequential := newNamespacedTrees(t, 100)
+	err := commitTrees(sequential, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel := newNamespacedTrees(t, 100)
+	err = commitTrees(parallel, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range sequential {
+		if !bytes.Equal(sequential[i].GetRoot(), parallel[i].GetRoot()) {
+			t.Errorf("root of tree %d differs", i)
+		}
+	}
+}
+
+func benchmarkCommit(b *testing.B, parallel bool) {
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		trees := newCommitTrees(10000)
+		b.StartTimer()
+		err := commitTrees(trees, parallel)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCommitSequential(b *testing.B) {
+	benchmarkCommit(b, false)
+}
+
+func BenchmarkCommitParallel(b *testing.B) {
+	benchmarkCommit(b, true)
+}
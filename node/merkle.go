@@ -3,10 +3,14 @@ package node
 import (
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/tokentransfer/go-MerklePatriciaTree/mpt"
 
 	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/metrics"
 	"github.com/tokentransfer/chain/store"
 
 	libblock "github.com/tokentransfer/interfaces/block"
@@ -15,17 +19,53 @@ import (
 	libstore "github.com/tokentransfer/interfaces/store"
 )
 
+// storeErrors wraps the read errors of the store under a trie, so that they
+// can be told from the errors of the trie for missing keys.
+type storeErrors struct {
+	libstore.KvService
+}
+
+type storeError struct {
+	err error
+}
+
+func (e *storeError) Error() string {
+	return e.err.Error()
+}
+
+func (s *storeErrors) GetData(key []byte) ([]byte, error) {
+	value, err := s.KvService.GetData(key)
+	if err != nil {
+		return nil, &storeError{err}
+	}
+	return value, nil
+}
+
+func (s *storeErrors) GetDatas(keys [][]byte) ([][]byte, error) {
+	values, err := s.KvService.GetDatas(keys)
+	if err != nil {
+		return nil, &storeError{err}
+	}
+	return values, nil
+}
+
 type MerkleTree struct {
 	mt *mpt.Trie
 	cs libcrypto.CryptoService
 	ss libstore.KvService
+
+	committed []byte // the root of the last commit
+
+	metrics *treeMetrics
 }
 
 func NewMerkleTree(cs libcrypto.CryptoService, ss libstore.KvService) *MerkleTree {
+	mt := mpt.New(cs, &storeErrors{ss})
 	return &MerkleTree{
-		mt: mpt.New(cs, ss),
-		cs: cs,
-		ss: ss,
+		mt:        mt,
+		cs:        cs,
+		ss:        ss,
+		committed: mt.RootHash(),
 	}
 }
 
@@ -33,11 +73,33 @@ func (t *MerkleTree) GetRoot() []byte {
 	return t.mt.RootHash()
 }
 
+// GetCommittedRoot returns the root of the last commit, whose nodes are in
+// the store once the commit is written.
+func (t *MerkleTree) GetCommittedRoot() []byte {
+	return t.committed
+}
+
+// Instrument records the latency of the tree operations in the registry.
+func (t *MerkleTree) Instrument(r *metrics.Registry, name string) {
+	t.metrics = newTreeMetrics(r, name)
+}
+
 func (t *MerkleTree) Commit() error {
-	return t.mt.Commit()
+	if t.metrics != nil {
+		defer observeSince(t.metrics.commit, time.Now())
+	}
+	err := t.mt.Commit()
+	if err != nil {
+		return err
+	}
+	t.committed = t.mt.RootHash()
+	return nil
 }
 
 func (t *MerkleTree) Cancel() error {
+	if t.metrics != nil {
+		t.metrics.cancel.Inc()
+	}
 	return t.mt.Abort()
 }
 
@@ -45,11 +107,27 @@ func (t *MerkleTree) Verify(key []byte) ([]byte, error) {
 	return nil, errors.New("unsupport")
 }
 
+// GetData returns the value of the key. A key missing from the trie is an
+// error for which IsNonexists is true, unlike a failure of the store.
 func (t *MerkleTree) GetData(key []byte) ([]byte, error) {
-	return t.mt.Get(key)
+	if t.metrics != nil {
+		defer observeSince(t.metrics.get, time.Now())
+	}
+	value, err := t.mt.Get(key)
+	if err != nil {
+		var se *storeError
+		if errors.As(err, &se) {
+			return nil, se.err
+		}
+		return nil, ErrorOfNonexists("key", string(key))
+	}
+	return value, nil
 }
 
 func (t *MerkleTree) PutData(key, value []byte) error {
+	if t.metrics != nil {
+		defer observeSince(t.metrics.put, time.Now())
+	}
 	return t.mt.Put(key, value)
 }
 
@@ -62,7 +140,7 @@ func (t *MerkleTree) Start() error {
 }
 
 func (t *MerkleTree) Close() error {
-	return nil
+	return t.ss.Close()
 }
 
 func (t *MerkleTree) PutDatas(keys [][]byte, values [][]byte) error {
@@ -134,9 +212,27 @@ type MerkleService struct {
 	tm *MerkleTree // transaction
 	sm *MerkleTree // state
 
+	keyring *store.Keyring
+
+	blocks *store.LRU // decoded headers by hash
+	states *store.LRU // decoded states by hash
+
+	metrics *serviceMetrics
+
+	lock    sync.RWMutex // held by Commit and Cancel
+	head    *blockHead   // last committed block
+	pending *blockHead   // last block put since the last commit
+	unsaved []blockSeqs  // commits which may not be written yet
+	tracked *uint64      // index of the last block of the last commit
+
 	crypto libcrypto.CryptoService
 }
 
+type blockHead struct {
+	index uint64
+	hash  libcore.Hash
+}
+
 func NewMerkleService(c libcore.Config, cs libcrypto.CryptoService) (libstore.MerkleService, error) {
 	service := &MerkleService{
 		config: c,
@@ -156,52 +252,154 @@ func NewMerkleService(c libcore.Config, cs libcrypto.CryptoService) (libstore.Me
 func (service *MerkleService) Init(c libcore.Config) error {
 	service.config = c
 
-	indexdb := &store.LevelService{Name: "index"}
-	err := indexdb.Init(c)
+	keyring, err := store.GetKeyring(c)
 	if err != nil {
 		return err
 	}
-	err = indexdb.Start()
-	if err != nil {
-		return err
+	service.keyring = keyring
+
+	cacheSize := store.GetCacheSize(c)
+	if cacheSize > 0 {
+		service.blocks = store.NewLRU(cacheSize)
+		service.states = store.NewLRU(cacheSize)
 	}
-	service.im = NewMerkleTree(service.crypto, indexdb)
 
-	blockdb := &store.LevelService{Name: "block"}
-	err = blockdb.Init(c)
+	service.im, err = service.openTree("index")
 	if err != nil {
 		return err
 	}
-	err = blockdb.Start()
+	service.bm, err = service.openTree("block")
 	if err != nil {
 		return err
 	}
-	service.bm = NewMerkleTree(service.crypto, blockdb)
-
-	txdb := &store.LevelService{Name: "transaction"}
-	err = txdb.Init(c)
+	service.tm, err = service.openTree("transaction")
 	if err != nil {
 		return err
 	}
-	err = txdb.Start()
+	service.sm, err = service.openTree("receipt")
 	if err != nil {
 		return err
 	}
-	service.tm = NewMerkleTree(service.crypto, txdb)
+	if r := service.registry(); r != nil {
+		service.metrics = newServiceMetrics(r)
+	}
+	return nil
+}
 
-	statedb := &store.LevelService{Name: "receipt"}
-	err = statedb.Init(c)
+// openTree opens the named store on the configured backend, wrapped with
+// metering, encryption and caching when the config enables them.
+func (service *MerkleService) openTree(name string) (*MerkleTree, error) {
+	c := service.config
+	db, err := store.NewKvService(store.GetBackend(c), name, c)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = statedb.Start()
-	if err != nil {
-		return err
+	r := service.registry()
+	if r != nil {
+		db = store.NewMeteredService(db, r, name)
+	}
+	if service.keyring != nil {
+		db = store.NewCryptService(db, service.keyring, store.GetEncryptKeys(c))
+	}
+	if cacheSize := store.GetCacheSize(c); cacheSize > 0 {
+		db = store.NewCacheService(db, cacheSize)
+	}
+	if depth := store.GetPipelineDepth(c); depth > 0 {
+		db = store.NewAsyncService(db, depth)
+	}
+	t := NewMerkleTree(service.crypto, db)
+	if r != nil {
+		t.Instrument(r, name)
+	}
+	return t, nil
+}
+
+// registry returns the metrics registry of the config, labelled with the data
+// directory to tell the services sharing it apart, or nil if metrics are
+// disabled.
+func (service *MerkleService) registry() *metrics.Registry {
+	r := metrics.GetRegistry(service.config)
+	if r == nil {
+		return nil
+	}
+	return r.With("service", service.config.GetDataDir())
+}
+
+// RotateKey makes the key with the id active in every encrypted store and
+// re-encrypts the stored data in the background.
+func (service *MerkleService) RotateKey(id byte) error {
+	if service.keyring == nil {
+		return errors.New("encryption is disabled")
+	}
+	for _, t := range service.trees() {
+		cs := findCryptService(t.ss)
+		if cs == nil {
+			continue
+		}
+		err := cs.Rotate(id)
+		if err != nil {
+			return err
+		}
 	}
-	service.sm = NewMerkleTree(service.crypto, statedb)
 	return nil
 }
 
+// WaitKeyRotation blocks until the re-encryption started by RotateKey is done.
+func (service *MerkleService) WaitKeyRotation() error {
+	for _, t := range service.trees() {
+		cs := findCryptService(t.ss)
+		if cs == nil {
+			continue
+		}
+		err := cs.WaitRotation()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findCryptService(db libstore.KvService) *store.CryptService {
+	for {
+		if cs, ok := db.(*store.CryptService); ok {
+			return cs
+		}
+		w, ok := db.(store.Wrapper)
+		if !ok {
+			return nil
+		}
+		db = w.Unwrap()
+	}
+}
+
+// GetCacheStats returns the statistics of the caches enabled by the config,
+// keyed by store or object name.
+func (service *MerkleService) GetCacheStats() map[string]store.CacheStats {
+	stats := make(map[string]store.CacheStats)
+	trees := map[string]*MerkleTree{
+		"index":       service.im,
+		"block":       service.bm,
+		"transaction": service.tm,
+		"receipt":     service.sm,
+	}
+	for name, t := range trees {
+		db := t.ss
+		if as, ok := db.(*store.AsyncService); ok {
+			db = as.KvService
+		}
+		if cs, ok := db.(*store.CacheService); ok {
+			stats[name] = cs.Stats()
+		}
+	}
+	if service.blocks != nil {
+		stats["blocks"] = service.blocks.Stats()
+	}
+	if service.states != nil {
+		stats["states"] = service.states.Stats()
+	}
+	return stats
+}
+
 func (service *MerkleService) Start() error {
 	return nil
 }
@@ -260,18 +458,31 @@ func (service *MerkleService) PutState(state libblock.State, s ...interface{}) e
 	if err != nil {
 		return err
 	}
+	service.metrics.wrote("state")
 	return nil
 }
 
+// GetStateByHash returns the state with the hash. The cache keeps the
+// decoded states, and every caller gets its own copy.
 func (service *MerkleService) GetStateByHash(h libcore.Hash, s ...interface{}) (libblock.State, error) {
+	if service.states != nil {
+		if state, ok := service.states.Get(string(h)); ok {
+			return block.CopyState(state.(libblock.State)), nil
+		}
+	}
 	data, err := service.sm.GetData(h)
+	service.metrics.read("state", err)
 	if err != nil {
-		return nil, ErrorOfNonexists("state", h.String())
+		return nil, orNonexists(err, "state", h.String())
 	}
 	state, err := block.ReadState(data)
 	if err != nil {
 		return nil, err
 	}
+	if service.states != nil {
+		service.states.Add(string(h), state, int64(len(data)))
+		return block.CopyState(state), nil
+	}
 	return state, nil
 }
 
@@ -279,7 +490,7 @@ func (service *MerkleService) GetStateByTypeAndKey(stateType libblock.StateType,
 	stateTypeAndKey := getStateKeyWithType(stateKey, stateType)
 	h, err := service.im.GetData([]byte(stateTypeAndKey))
 	if err != nil {
-		return nil, ErrorOfNonexists("state", stateTypeAndKey)
+		return nil, orNonexists(err, "state", stateTypeAndKey)
 	}
 	return service.GetStateByHash(libcore.Hash(h))
 }
@@ -288,7 +499,7 @@ func (service *MerkleService) GetStateByTypeAndAddress(stateType libblock.StateT
 	stateTypeTypeAndAddress := getStateKeyWithType(account.String(), stateType)
 	h, err := service.im.GetData([]byte(stateTypeTypeAndAddress))
 	if err != nil {
-		return nil, ErrorOfNonexists("state", stateTypeTypeAndAddress)
+		return nil, orNonexists(err, "state", stateTypeTypeAndAddress)
 	}
 	return service.GetStateByHash(libcore.Hash(h))
 }
@@ -297,7 +508,7 @@ func (service *MerkleService) GetStateByAddressAndIndex(account libcore.Address,
 	stateAddressAndIndexKey := getStateKey(fmt.Sprintf("%s:%d", account.String(), index))
 	h, err := service.im.GetData([]byte(stateAddressAndIndexKey))
 	if err != nil {
-		return nil, ErrorOfNonexists("state", stateAddressAndIndexKey)
+		return nil, orNonexists(err, "state", stateAddressAndIndexKey)
 	}
 	return service.GetStateByHash(libcore.Hash(h))
 }
@@ -306,7 +517,7 @@ func (service *MerkleService) GetStateByAddress(account libcore.Address, s ...in
 	stateAddressKey := getStateKey(account.String())
 	h, err := service.im.GetData([]byte(stateAddressKey))
 	if err != nil {
-		return nil, ErrorOfNonexists("state", stateAddressKey)
+		return nil, orNonexists(err, "state", stateAddressKey)
 	}
 	return service.GetStateByHash(libcore.Hash(h))
 }
@@ -334,12 +545,14 @@ func (service *MerkleService) PutTransaction(txWithData libblock.TransactionWith
 	if err != nil {
 		return err
 	}
+	service.metrics.wrote("transaction")
 
 	return nil
 }
 
 func (service *MerkleService) GetTransactionByHash(h libcore.Hash, s ...interface{}) (libblock.TransactionWithData, error) {
 	data, err := service.tm.GetData(h)
+	service.metrics.read("transaction", err)
 	if err != nil {
 		return nil, ErrorOfNonexists("transaction", h.String())
 	}
@@ -367,7 +580,17 @@ func (service *MerkleService) GetTransactionRoot() libcore.Hash {
 func (service *MerkleService) PutBlock(b libblock.Block, s ...interface{}) error {
 	cs := service.crypto
 
-	h, data, err := cs.Raw(b, libcrypto.RawBinary)
+	h, _, err := cs.Raw(b, libcrypto.RawBinary)
+	if err != nil {
+		return err
+	}
+	header := block.NewBlockHeader(b)
+	header.SetHash(h)
+	data, err := header.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	body, err := block.MarshalBody(b)
 	if err != nil {
 		return err
 	}
@@ -375,25 +598,154 @@ func (service *MerkleService) PutBlock(b libblock.Block, s ...interface{}) error
 	if err != nil {
 		return err
 	}
+	err = service.bm.PutData(getBodyKey(h), body)
+	if err != nil {
+		return err
+	}
 	name := getBlockKey(b.GetIndex())
 	err = service.im.PutData([]byte(name), h[:])
 	if err != nil {
 		return err
 	}
+
+	service.metrics.wrote("block")
+
+	service.lock.Lock()
+	if service.pending == nil || b.GetIndex() >= service.pending.index {
+		service.pending = &blockHead{b.GetIndex(), h}
+	}
+	service.lock.Unlock()
 	return nil
 }
 
-func (service *MerkleService) GetBlockByHash(hash libcore.Hash, s ...interface{}) (libblock.Block, error) {
+// GetHead returns the index and hash of the last committed block.
+func (service *MerkleService) GetHead() (uint64, libcore.Hash, error) {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	return service.getHead()
+}
+
+func (service *MerkleService) getHead() (uint64, libcore.Hash, error) {
+	if service.head == nil {
+		head, err := service.findHead()
+		if err != nil {
+			return 0, nil, err
+		}
+		service.head = head
+	}
+	return service.head.index, service.head.hash, nil
+}
+
+func (service *MerkleService) hasBlock(index uint64) bool {
+	return service.im.HasData([]byte(getBlockKey(index)))
+}
+
+// findHead searches the index tree for the highest block index, as blocks
+// are stored with contiguous indexes.
+func (service *MerkleService) findHead() (*blockHead, error) {
+	lo := uint64(0)
+	if !service.hasBlock(lo) {
+		lo = 1
+		if !service.hasBlock(lo) {
+			return nil, ErrorOfNonexists("block", "head")
+		}
+	}
+	hi := lo + 1
+	for service.hasBlock(hi) {
+		lo = hi
+		hi = hi * 2
+	}
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if service.hasBlock(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	h, err := service.im.GetData([]byte(getBlockKey(lo)))
+	if err != nil {
+		return nil, err
+	}
+	return &blockHead{lo, libcore.Hash(h)}, nil
+}
+
+// getHeader returns a copy of the header of the block, or the whole block if
+// it was stored before headers and bodies were split. The cache keeps the
+// decoded headers.
+func (service *MerkleService) getHeader(hash libcore.Hash) (*block.BlockHeader, *block.Block, error) {
+	if service.blocks != nil {
+		if header, ok := service.blocks.Get(string(hash)); ok {
+			h := *header.(*block.BlockHeader)
+			return &h, nil, nil
+		}
+	}
 	data, err := service.bm.GetData(hash)
+	service.metrics.read("block", err)
+	if err != nil {
+		return nil, nil, ErrorOfNonexists("block", hash.String())
+	}
+	if core.GetMeta(data) == core.CORE_BLOCK {
+		b := &block.Block{}
+		err = b.UnmarshalBinary(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		b.SetHash(hash)
+		return nil, b, nil
+	}
+	header := &block.BlockHeader{}
+	err = header.UnmarshalBinary(data)
 	if err != nil {
-		return nil, ErrorOfNonexists("block", hash.String())
+		return nil, nil, err
+	}
+	header.SetHash(hash)
+	if service.blocks != nil {
+		service.blocks.Add(string(hash), header, int64(len(data)))
+		h := *header
+		return &h, nil, nil
+	}
+	return header, nil, nil
+}
+
+func (service *MerkleService) GetBlockByHash(hash libcore.Hash, s ...interface{}) (libblock.Block, error) {
+	header, b, err := service.getHeader(hash)
+	if err != nil {
+		return nil, err
+	}
+	if b != nil {
+		return b, nil
 	}
-	b := &block.Block{}
-	err = b.UnmarshalBinary(data)
+	return block.NewLazyBlock(header, func() ([]byte, error) {
+		body, err := service.bm.GetData(getBodyKey(hash))
+		if err != nil {
+			return nil, ErrorOfNonexists("block body", hash.String())
+		}
+		return body, nil
+	}), nil
+}
+
+// GetHeaderByHash returns the header of the block without reading its
+// transactions and states.
+func (service *MerkleService) GetHeaderByHash(hash libcore.Hash) (*block.BlockHeader, error) {
+	header, b, err := service.getHeader(hash)
 	if err != nil {
 		return nil, err
 	}
-	return b, nil
+	if b != nil {
+		return block.NewBlockHeader(b), nil
+	}
+	return header, nil
+}
+
+func (service *MerkleService) GetHeaderByIndex(index uint64) (*block.BlockHeader, error) {
+	name := getBlockKey(index)
+	data, err := service.im.GetData([]byte(name))
+	if err != nil {
+		return nil, ErrorOfNonexists("block", fmt.Sprintf("%d", index))
+	}
+	return service.GetHeaderByHash(libcore.Hash(data))
 }
 
 func (service *MerkleService) GetBlockByIndex(index uint64, s ...interface{}) (libblock.Block, error) {
@@ -407,26 +759,85 @@ func (service *MerkleService) GetBlockByIndex(index uint64, s ...interface{}) (l
 }
 
 func (service *MerkleService) Commit(s ...interface{}) error {
-	err := service.im.Commit()
-	if err != nil {
-		return err
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	if service.metrics != nil {
+		defer observeSince(service.metrics.commit, time.Now())
 	}
-	err = service.bm.Commit()
+
+	seqs, err := service.commit()
 	if err != nil {
 		return err
 	}
-	err = service.tm.Commit()
+	return service.waitSeqs(seqs)
+}
+
+func (service *MerkleService) commit() ([]uint64, error) {
+	err := commitTrees(service.trees(), true)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = service.sm.Commit()
+	seqs, err := service.seal()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if service.pending != nil {
+		service.head = service.pending
+		service.pending = nil
+		service.track(service.head.index, seqs)
+	}
+	return seqs, nil
+}
+
+// commitTrees commits the trees, concurrently if parallel is set. A root only
+// depends on the entries of its own tree, and the trees never share keys, even
+// when their stores share a database as with the namespaced backend, so the
+// roots don't depend on the order. The dirty nodes within a tree are still
+// hashed on one goroutine, by the trie package.
+func commitTrees(trees []*MerkleTree, parallel bool) error {
+	if !parallel {
+		for _, t := range trees {
+			err := t.Commit()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, len(trees))
+	var wg sync.WaitGroup
+	for i, t := range trees {
+		wg.Add(1)
+		go func(i int, t *MerkleTree) {
+			defer wg.Done()
+			errs[i] = t.Commit()
+		}(i, t)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
 func (service *MerkleService) Cancel(s ...interface{}) error {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	service.pending = nil
+
+	// cached objects may have been read from the uncommitted tries
+	if service.blocks != nil {
+		service.blocks.Purge()
+	}
+	if service.states != nil {
+		service.states.Purge()
+	}
+
 	err := service.im.Cancel()
 	if err != nil {
 		return err
@@ -454,6 +865,10 @@ func getBlockKey(index uint64) string {
 	return fmt.Sprintf("block@%d", index)
 }
 
+func getBodyKey(h libcore.Hash) []byte {
+	return append([]byte("body@"), h...)
+}
+
 func getTransactionKey(key string) string {
 	return fmt.Sprintf("transaction@%s", key)
 }
@@ -466,6 +881,27 @@ func getStateKeyWithType(key string, t libblock.StateType) string {
 	return fmt.Sprintf("state@%s@%s", t.String(), key)
 }
 
+var errNonexists = errors.New("can't find")
+
 func ErrorOfNonexists(t string, target string) error {
-	return fmt.Errorf("can't find %s: %s", t, target)
+	return fmt.Errorf("%w %s: %s", errNonexists, t, target)
+}
+
+// IsNonexists reports whether the error is for a missing entry, rather than
+// a failure of the store.
+func IsNonexists(err error) bool {
+	return errors.Is(err, errNonexists)
+}
+
+// orNonexists returns the error of a missing entry of the type for a missing
+// key, or else the error.
+func orNonexists(err error, t string, target string) error {
+	if IsNonexists(err) {
+		return ErrorOfNonexists(t, target)
+	}
+	return err
+}
+
+func ErrorOfInvalid(t string, target string) error {
+	return fmt.Errorf("invalid %s: %s", t, target)
 }
@@ -0,0 +1,68 @@
+package node
+
+import (
+	"time"
+
+	"github.com/tokentransfer/chain/metrics"
+)
+
+type treeMetrics struct {
+	get    *metrics.Histogram
+	put    *metrics.Histogram
+	commit *metrics.Histogram
+	cancel *metrics.Counter
+}
+
+func newTreeMetrics(r *metrics.Registry, name string) *treeMetrics {
+	return &treeMetrics{
+		get:    r.Histogram("trie_get_seconds", "Latency of trie gets.", "tree", name),
+		put:    r.Histogram("trie_put_seconds", "Latency of trie puts.", "tree", name),
+		commit: r.Histogram("trie_commit_seconds", "Latency of trie commits.", "tree", name),
+		cancel: r.Counter("trie_cancels_total", "Number of cancelled trie changes.", "tree", name),
+	}
+}
+
+type serviceMetrics struct {
+	commit *metrics.Histogram
+	puts   map[string]*metrics.Counter
+	gets   map[string]*metrics.Counter
+	misses map[string]*metrics.Counter
+}
+
+func newServiceMetrics(r *metrics.Registry) *serviceMetrics {
+	m := &serviceMetrics{
+		commit: r.Histogram("merkle_commit_seconds", "Latency of committing all trees."),
+		puts:   make(map[string]*metrics.Counter),
+		gets:   make(map[string]*metrics.Counter),
+		misses: make(map[string]*metrics.Counter),
+	}
+	for _, kind := range []string{"block", "transaction", "state"} {
+		m.puts[kind] = r.Counter("merkle_puts_total", "Number of objects put.", "kind", kind)
+		m.gets[kind] = r.Counter("merkle_gets_total", "Number of objects read.", "kind", kind)
+		m.misses[kind] = r.Counter("merkle_misses_total", "Number of objects not found.", "kind", kind)
+	}
+	return m
+}
+
+func (m *serviceMetrics) read(kind string, err error) {
+	if m == nil {
+		return
+	}
+	m.gets[kind].Inc()
+	if err != nil {
+		m.misses[kind].Inc()
+	}
+}
+
+func (m *serviceMetrics) wrote(kind string) {
+	if m == nil {
+		return
+	}
+	m.puts[kind].Inc()
+}
+
+func observeSince(h *metrics.Histogram, start time.Time) {
+	if h != nil {
+		h.ObserveSince(start)
+	}
+}
@@ -0,0 +1,289 @@
+package node
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/tokentransfer/chain/store"
+
+	libcore "github.com/tokentransfer/interfaces/core"
+	libcrypto "github.com/tokentransfer/interfaces/crypto"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+const (
+	MIGRATE_PROGRESS = "migrate.progress"
+
+	migrateBatchSize = 1000
+)
+
+// MigrateProgress is saved in the target data directory after every batch, so
+// an interrupted migration resumes after the last copied key. A target kept
+// in a snapshot file is rewritten whole when it is saved, so it is only saved
+// once its store is copied, and an interrupted copy starts over.
+type MigrateProgress struct {
+	Stores map[string]*StoreProgress `json:"stores"`
+}
+
+type StoreProgress struct {
+	LastKey string `json:"last_key"`
+	Entries uint64 `json:"entries"`
+	Done    bool   `json:"done"`
+}
+
+// Migrate copies every store of the source config to the backend of the
+// target config, then checks that the target has the same roots and head as
+// the source. The entries are decrypted with the keys of the source config and
+// encrypted again with those of the target config. The source must not be
+// written during the migration, and its backend must list keys in order for
+// the migration to resume.
+func Migrate(from libcore.Config, to libcore.Config, cs libcrypto.CryptoService) (*MigrateProgress, error) {
+	progressFile := path.Join(to.GetDataDir(), MIGRATE_PROGRESS)
+	progress, err := loadMigrateProgress(progressFile)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range treeNames() {
+		p, ok := progress.Stores[name]
+		if !ok {
+			p = &StoreProgress{}
+			progress.Stores[name] = p
+		}
+		if p.Done {
+			continue
+		}
+		err = migrateStore(from, to, name, p, func() error {
+			return saveMigrateProgress(progressFile, progress)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = verifyMigration(from, to, cs)
+	if err != nil {
+		return nil, err
+	}
+	err = os.Remove(progressFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return progress, nil
+}
+
+func openStore(c libcore.Config, name string) (libstore.KvService, error) {
+	return store.NewKvService(store.GetBackend(c), name, c)
+}
+
+// openTarget opens the named store of the config, encrypting the entries
+// written to it when the config enables encryption.
+func openTarget(c libcore.Config, name string) (libstore.KvService, error) {
+	keyring, err := store.GetKeyring(c)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openStore(c, name)
+	if err != nil {
+		return nil, err
+	}
+	if keyring != nil {
+		db = store.NewCryptService(db, keyring, store.GetEncryptKeys(c))
+	}
+	return db, nil
+}
+
+// sourceDecoder returns the function decoding the entries listed from a store
+// of the config. The key it returns is nil for the metadata of an encrypted
+// store, which isn't copied.
+func sourceDecoder(c libcore.Config, db libstore.KvService) (func(k []byte, v []byte) ([]byte, []byte, error), error) {
+	keyring, err := store.GetKeyring(c)
+	if err != nil {
+		return nil, err
+	}
+	if keyring == nil {
+		return func(k []byte, v []byte) ([]byte, []byte, error) {
+			return k, v, nil
+		}, nil
+	}
+	cs := store.NewCryptService(db, keyring, store.GetEncryptKeys(c))
+	return func(k []byte, v []byte) ([]byte, []byte, error) {
+		if store.IsCryptMetadata(k) {
+			return nil, nil, nil
+		}
+		return cs.Decode(k, v)
+	}, nil
+}
+
+func migrateStore(from libcore.Config, to libcore.Config, name string, p *StoreProgress, save func() error) error {
+	// the source is listed in the order of its stored keys, which is also
+	// the order the progress is kept in when the keys are encrypted
+	source, err := openStore(from, name)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	decode, err := sourceDecoder(from, source)
+	if err != nil {
+		return err
+	}
+	target, err := openTarget(to, name)
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+	memory, snapshot := store.Unwrap(target).(*store.MemoryService)
+
+	lastKey, err := hex.DecodeString(p.LastKey)
+	if err != nil {
+		return err
+	}
+	resume := len(p.LastKey) > 0
+
+	keys := make([][]byte, 0, migrateBatchSize)
+	values := make([][]byte, 0, migrateBatchSize)
+	var batchKey []byte
+	entries := p.Entries
+	flush := func() error {
+		if len(keys) == 0 {
+			return nil
+		}
+		err := target.PutDatas(keys, values)
+		if err != nil {
+			return err
+		}
+		entries += uint64(len(keys))
+		keys = keys[:0]
+		values = values[:0]
+		if snapshot {
+			return nil
+		}
+		err = target.Flush()
+		if err != nil {
+			return err
+		}
+		p.LastKey = hex.EncodeToString(batchKey)
+		p.Entries = entries
+		return save()
+	}
+
+	var previous []byte
+	err = source.ListData(func(k []byte, v []byte) error {
+		if previous != nil && bytes.Compare(k, previous) <= 0 {
+			return fmt.Errorf("store %s doesn't list keys in order", name)
+		}
+		previous = append(previous[:0], k...)
+		if resume && bytes.Compare(k, lastKey) <= 0 {
+			return nil
+		}
+
+		key, value, err := decode(k, v)
+		if err != nil {
+			return err
+		}
+		if key == nil {
+			return nil
+		}
+		keys = append(keys, append([]byte(nil), key...))
+		values = append(values, append([]byte(nil), value...))
+		batchKey = append(batchKey[:0], k...)
+		if len(keys) == migrateBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	err = flush()
+	if err != nil {
+		return err
+	}
+	if snapshot {
+		err = memory.Save()
+	} else {
+		err = target.Flush()
+	}
+	if err != nil {
+		return err
+	}
+	p.LastKey = hex.EncodeToString(previous)
+	p.Entries = entries
+	p.Done = true
+	return save()
+}
+
+func verifyMigration(from libcore.Config, to libcore.Config, cs libcrypto.CryptoService) error {
+	source := &MerkleService{config: from, crypto: cs}
+	err := source.Init(from)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	target := &MerkleService{config: to, crypto: cs}
+	err = target.Init(to)
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	names := treeNames()
+	targetTrees := target.trees()
+	for i, t := range source.trees() {
+		if !bytes.Equal(t.GetRoot(), targetTrees[i].GetRoot()) {
+			return ErrorOfInvalid("root", names[i])
+		}
+	}
+
+	sourceIndex, sourceHash, sourceErr := source.GetHead()
+	targetIndex, targetHash, targetErr := target.GetHead()
+	if (sourceErr == nil) != (targetErr == nil) {
+		return ErrorOfInvalid("head", "existence")
+	}
+	if sourceErr == nil && (sourceIndex != targetIndex || !bytes.Equal(sourceHash, targetHash)) {
+		return ErrorOfInvalid("head", fmt.Sprintf("%d", sourceIndex))
+	}
+	return nil
+}
+
+func loadMigrateProgress(file string) (*MigrateProgress, error) {
+	progress := &MigrateProgress{
+		Stores: make(map[string]*StoreProgress),
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return progress, nil
+		}
+		return nil, err
+	}
+	err = json.Unmarshal(data, progress)
+	if err != nil {
+		return nil, err
+	}
+	if progress.Stores == nil {
+		progress.Stores = make(map[string]*StoreProgress)
+	}
+	return progress, nil
+}
+
+func saveMigrateProgress(file string, progress *MigrateProgress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(path.Dir(file), 0755)
+	if err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	err = os.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
@@ -0,0 +1,68 @@
+package node
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tokentransfer/chain/crypto"
+	"github.com/tokentransfer/chain/store"
+)
+
+const testKeys = "1:000102030405060708090a0b0c0d0e0f000102030405060708090a0b0c0d0e0f"
+
+func TestMigrateRoundTrip(t *testing.T) {
+	plain := &testConfig{dir: t.TempDir(), backend: store.SNAPSHOT}
+	source := newTestService(t, plain)
+	alice := withValue(t, testAccountState(t, "alice"), "100")
+	err := source.PutState(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = source.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = source.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// plain to encrypted and back, on other backends
+	configs := []*testConfig{
+		plain,
+		{dir: t.TempDir(), backend: store.NAMESPACED, keys: testKeys, encryptKeys: true},
+		{dir: t.TempDir(), backend: store.SNAPSHOT},
+	}
+	cs := &crypto.CryptoService{}
+	for i := 1; i < len(configs); i++ {
+		_, err = Migrate(configs[i-1], configs[i], cs)
+		if err != nil {
+			t.Fatalf("migration %d: %v", i, err)
+		}
+	}
+
+	encrypted := newTestService(t, configs[1])
+	count := 0
+	err = store.Unwrap(encrypted.sm.ss).ListData(func(key []byte, value []byte) error {
+		if key[0] != 1 || value[0] != 1 {
+			t.Fatalf("entry not encrypted: %x", key)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count == 0 {
+		t.Fatal("empty encrypted store")
+	}
+
+	restored := newTestService(t, configs[len(configs)-1])
+	if !bytes.Equal(restored.GetStateRoot(), encrypted.GetStateRoot()) {
+		t.Fatal("state roots differ")
+	}
+	_, err = restored.GetStateByHash(alice.GetHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
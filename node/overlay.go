@@ -0,0 +1,162 @@
+package node
+
+import (
+	libblock "github.com/tokentransfer/interfaces/block"
+)
+
+// StateReader is the part of MerkleService read by an Overlay.
+type StateReader interface {
+	GetStateByTypeAndKey(stateType libblock.StateType, stateKey string, s ...interface{}) (libblock.State, error)
+}
+
+// StateWriter is the part of MerkleService written by Overlay.Flush.
+type StateWriter interface {
+	PutState(state libblock.State, s ...interface{}) error
+}
+
+type stateID struct {
+	stateType libblock.StateType
+	key       string
+}
+
+func getStateID(state libblock.State) stateID {
+	return stateID{state.GetStateType(), state.GetStateKey()}
+}
+
+type journalEntry struct {
+	id    stateID
+	prev  libblock.State // nil if the state wasn't written before
+	first bool           // the write added the id to the write order
+}
+
+// Overlay keeps the states written while executing a block in memory, above
+// the states of a StateReader. Every write is recorded in a journal, so the
+// writes of a failed transaction can be reverted to a snapshot taken before
+// it. The written states are given to the StateWriter only by Flush.
+//
+// The states returned by GetState must not be modified; a changed state is
+// put as a new object. An Overlay must not be used concurrently.
+type Overlay struct {
+	base StateReader
+
+	reads  map[stateID]libblock.State // states read from the base
+	writes map[stateID]libblock.State
+	order  []stateID // written ids, in the order of their first write
+
+	journal   []journalEntry
+	snapshots []int // journal length at each snapshot
+}
+
+func NewOverlay(base StateReader) *Overlay {
+	return &Overlay{
+		base:   base,
+		reads:  make(map[stateID]libblock.State),
+		writes: make(map[stateID]libblock.State),
+	}
+}
+
+// GetState returns the state written last in the overlay, or else the state
+// of the base.
+func (o *Overlay) GetState(stateType libblock.StateType, stateKey string) (libblock.State, error) {
+	id := stateID{stateType, stateKey}
+	if state, ok := o.writes[id]; ok {
+		return state, nil
+	}
+	if state, ok := o.reads[id]; ok {
+		return state, nil
+	}
+	state, err := o.base.GetStateByTypeAndKey(stateType, stateKey)
+	if err != nil {
+		return nil, err
+	}
+	o.reads[id] = state
+	return state, nil
+}
+
+// PutState writes the state to the overlay, replacing the state with the
+// same type and key.
+func (o *Overlay) PutState(state libblock.State) {
+	id := getStateID(state)
+	prev, ok := o.writes[id]
+	o.journal = append(o.journal, journalEntry{id, prev, !ok})
+	if !ok {
+		o.order = append(o.order, id)
+	}
+	o.writes[id] = state
+}
+
+// Snapshot returns the id of the current version of the overlay, to pass to
+// RevertToSnapshot.
+func (o *Overlay) Snapshot() int {
+	o.snapshots = append(o.snapshots, len(o.journal))
+	return len(o.snapshots) - 1
+}
+
+// RevertToSnapshot undoes the writes made since the snapshot with the id was
+// taken. The snapshot and the later ones can't be used again.
+func (o *Overlay) RevertToSnapshot(id int) {
+	if id < 0 || id >= len(o.snapshots) {
+		return
+	}
+	l := o.snapshots[id]
+	for i := len(o.journal) - 1; i >= l; i-- {
+		entry := o.journal[i]
+		if entry.prev == nil {
+			delete(o.writes, entry.id)
+		} else {
+			o.writes[entry.id] = entry.prev
+		}
+		if entry.first {
+			o.order = o.order[:len(o.order)-1]
+		}
+	}
+	o.journal = o.journal[:l]
+	o.snapshots = o.snapshots[:id]
+}
+
+// ChangedSince returns the current value of the states written since the
+// snapshot with the id was taken, in the order of their first write.
+func (o *Overlay) ChangedSince(id int) []libblock.State {
+	l := 0
+	if id >= 0 && id < len(o.snapshots) {
+		l = o.snapshots[id]
+	}
+	seen := make(map[stateID]bool)
+	states := make([]libblock.State, 0)
+	for _, entry := range o.journal[l:] {
+		if seen[entry.id] {
+			continue
+		}
+		seen[entry.id] = true
+		states = append(states, o.writes[entry.id])
+	}
+	return states
+}
+
+// Dirty returns the written states, in the order of their first write.
+func (o *Overlay) Dirty() []libblock.State {
+	states := make([]libblock.State, len(o.order))
+	for i, id := range o.order {
+		states[i] = o.writes[id]
+	}
+	return states
+}
+
+// Flush puts the written states to w, in the order of their first write,
+// and clears the journal. The overlay keeps returning the written states.
+func (o *Overlay) Flush(w StateWriter) error {
+	for _, state := range o.Dirty() {
+		err := w.PutState(state)
+		if err != nil {
+			return err
+		}
+	}
+	for _, id := range o.order {
+		o.reads[id] = o.writes[id]
+	}
+	o.writes = make(map[stateID]libblock.State)
+	o.order = nil
+	o.journal = nil
+	o.snapshots = nil
+	return nil
+}
@@ -0,0 +1,114 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/tokentransfer/chain/account/jingtum"
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/core"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+// mapStates is a StateReader and StateWriter over a map.
+type mapStates struct {
+	states map[stateID]libblock.State
+	puts   []libblock.State
+}
+
+func newMapStates() *mapStates {
+	return &mapStates{states: make(map[stateID]libblock.State)}
+}
+
+func (m *mapStates) GetStateByTypeAndKey(stateType libblock.StateType, stateKey string, s ...interface{}) (libblock.State, error) {
+	state, ok := m.states[stateID{stateType, stateKey}]
+	if !ok {
+		return nil, ErrorOfNonexists("state", stateKey)
+	}
+	return state, nil
+}
+
+func (m *mapStates) PutState(state libblock.State, s ...interface{}) error {
+	m.states[getStateID(state)] = state
+	m.puts = append(m.puts, state)
+	return nil
+}
+
+func testAccountState(t *testing.T, password string) *block.AccountState {
+	key, err := jingtum.GenerateFamilySeed(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := key.GetAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	currency, err := libcore.NewSymbol("TEST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &block.AccountState{
+		State: block.State{
+			Account:   a,
+			StateType: block.ACCOUNT_STATE,
+		},
+		Amount: core.Amount{
+			Value:    core.Value{},
+			Currency: currency,
+		},
+	}
+}
+
+func withValue(t *testing.T, s *block.AccountState, value string) *block.AccountState {
+	v, err := core.NewValue(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := *s
+	c.Amount.Value = *v
+	return &c
+}
+
+func TestOverlayRevert(t *testing.T) {
+	base := newMapStates()
+	alice := withValue(t, testAccountState(t, "alice"), "100")
+	bob := testAccountState(t, "bob")
+	base.PutState(alice)
+	base.puts = nil
+
+	o := NewOverlay(base)
+	o.PutState(withValue(t, alice, "90"))
+	o.PutState(withValue(t, bob, "10"))
+
+	id := o.Snapshot()
+	o.PutState(withValue(t, alice, "0"))
+	o.PutState(withValue(t, bob, "100"))
+	changed := o.ChangedSince(id)
+	if len(changed) != 2 {
+		t.Fatalf("changed %d states, expected 2", len(changed))
+	}
+	o.RevertToSnapshot(id)
+
+	state, err := o.GetState(block.ACCOUNT_STATE, alice.GetStateKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := state.(*block.AccountState).Amount.Value; v.Value() != 90 {
+		t.Errorf("reverted value %d, expected 90", v.Value())
+	}
+
+	err = o.Flush(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(base.puts) != 2 {
+		t.Fatalf("flushed %d states, expected 2", len(base.puts))
+	}
+	if base.puts[0].GetStateKey() != alice.GetStateKey() || base.puts[1].GetStateKey() != bob.GetStateKey() {
+		t.Error("states not flushed in write order")
+	}
+	if v := base.puts[1].(*block.AccountState).Amount.Value; v.Value() != 10 {
+		t.Errorf("flushed value %d, expected 10", v.Value())
+	}
+}
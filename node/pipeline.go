@@ -0,0 +1,158 @@
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tokentransfer/chain/store"
+)
+
+// CommitAsync commits the trees like Commit, but returns once the changes
+// are buffered, while they are written in the background. Reads, including
+// those of the next block, see the buffered changes. It blocks while the
+// number of commits waiting to be written is the pipeline depth of the
+// config. Without a pipeline depth, it is the same as Commit.
+func (service *MerkleService) CommitAsync(s ...interface{}) error {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	if service.metrics != nil {
+		defer observeSince(service.metrics.commit, time.Now())
+	}
+
+	_, err := service.commit()
+	return err
+}
+
+// blockSeqs are the sequences of the tree layers holding the committed
+// blocks from the index from to the index index.
+type blockSeqs struct {
+	from  uint64
+	index uint64
+	seqs  []uint64
+}
+
+// track records the layers of a commit which ends with the block with the
+// index, and forgets the commits whose layers are all written.
+func (service *MerkleService) track(index uint64, seqs []uint64) {
+	from := uint64(0)
+	if service.tracked != nil {
+		from = *service.tracked + 1
+	}
+	service.tracked = &index
+
+	written := service.written()
+	unsaved := service.unsaved[:0]
+	for _, b := range service.unsaved {
+		if !seqsBelow(b.seqs, written) {
+			unsaved = append(unsaved, b)
+		}
+	}
+	service.unsaved = unsaved
+	if !seqsBelow(seqs, written) {
+		service.unsaved = append(service.unsaved, blockSeqs{from, index, seqs})
+	}
+}
+
+// seqsBelow reports whether every sequence is at most the one of the same
+// tree in max.
+func seqsBelow(seqs []uint64, max []uint64) bool {
+	for i := range seqs {
+		if seqs[i] > max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// durableSeqs returns the sequences to wait for until the committed block
+// with the index is written.
+func (service *MerkleService) durableSeqs(index uint64) []uint64 {
+	for _, b := range service.unsaved {
+		if index >= b.from && index <= b.index {
+			return b.seqs
+		}
+	}
+	// already written, the wait only returns the writer errors
+	return service.written()
+}
+
+// WaitDurable blocks until the committed block with the index, and every
+// block committed before it, is written to disk. Blocks committed after it
+// may still be in the pipeline.
+func (service *MerkleService) WaitDurable(index uint64) error {
+	service.lock.Lock()
+	head, _, err := service.getHead()
+	if err != nil || index > head {
+		service.lock.Unlock()
+		return ErrorOfNonexists("committed block", fmt.Sprintf("%d", index))
+	}
+	seqs := service.durableSeqs(index)
+	service.lock.Unlock()
+
+	return service.waitSeqs(seqs)
+}
+
+// Sync blocks until every committed block is written to disk.
+func (service *MerkleService) Sync() error {
+	service.lock.RLock()
+	seqs := service.sealed()
+	service.lock.RUnlock()
+
+	return service.waitSeqs(seqs)
+}
+
+// seal queues the committed changes of every tree for the background
+// writers, and returns the sequences to wait for.
+func (service *MerkleService) seal() ([]uint64, error) {
+	trees := service.trees()
+	seqs := make([]uint64, len(trees))
+	for i, t := range trees {
+		as, ok := t.ss.(*store.AsyncService)
+		if !ok {
+			continue
+		}
+		seq, err := as.Seal()
+		if err != nil {
+			return nil, err
+		}
+		seqs[i] = seq
+	}
+	return seqs, nil
+}
+
+func (service *MerkleService) sealed() []uint64 {
+	trees := service.trees()
+	seqs := make([]uint64, len(trees))
+	for i, t := range trees {
+		if as, ok := t.ss.(*store.AsyncService); ok {
+			seqs[i] = as.Sealed()
+		}
+	}
+	return seqs
+}
+
+func (service *MerkleService) written() []uint64 {
+	trees := service.trees()
+	seqs := make([]uint64, len(trees))
+	for i, t := range trees {
+		if as, ok := t.ss.(*store.AsyncService); ok {
+			seqs[i] = as.Written()
+		}
+	}
+	return seqs
+}
+
+func (service *MerkleService) waitSeqs(seqs []uint64) error {
+	for i, t := range service.trees() {
+		as, ok := t.ss.(*store.AsyncService)
+		if !ok || seqs[i] == 0 {
+			continue
+		}
+		err := as.Wait(seqs[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
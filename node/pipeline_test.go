@@ -0,0 +1,78 @@
+package node
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/store"
+
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+// gatedStore blocks the writes while the gate is locked.
+type gatedStore struct {
+	libstore.KvService
+
+	gate *sync.RWMutex
+}
+
+func (s *gatedStore) PutDatas(keys [][]byte, values [][]byte) error {
+	s.gate.RLock()
+	defer s.gate.RUnlock()
+
+	return s.KvService.PutDatas(keys, values)
+}
+
+func TestWaitDurable(t *testing.T) {
+	service := newTestService(t, &testConfig{depth: 4})
+	gate := &sync.RWMutex{}
+	for _, tree := range service.trees() {
+		as := tree.ss.(*store.AsyncService)
+		as.KvService = &gatedStore{as.KvService, gate}
+	}
+	commit := func(index uint64) {
+		err := service.PutBlock(&block.Block{BlockIndex: index})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = service.CommitAsync()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commit(0)
+	commit(1)
+	err := service.WaitDurable(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// block 2 stays in the pipeline, which doesn't hold back the older blocks
+	gate.Lock()
+	commit(2)
+	for _, index := range []uint64{0, 1} {
+		err = service.WaitDurable(index)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = service.WaitDurable(3)
+	if err == nil {
+		t.Fatal("waited for a block which isn't committed")
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- service.WaitDurable(2)
+	}()
+	gate.Unlock()
+	err = <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !seqsBelow(service.sealed(), service.written()) {
+		t.Fatal("block 2 not written")
+	}
+}
@@ -0,0 +1,232 @@
+package node
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/core"
+	"github.com/tokentransfer/chain/crypto"
+	"github.com/tokentransfer/chain/metrics"
+	"github.com/tokentransfer/chain/store"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+	libcore "github.com/tokentransfer/interfaces/core"
+)
+
+// testConfig selects a store backend, in-memory by default, in a temporary
+// directory.
+type testConfig struct {
+	libcore.Config
+
+	dir         string
+	backend     string
+	cacheSize   int64
+	registry    *metrics.Registry
+	keys        string
+	encryptKeys bool
+	depth       int
+}
+
+func (c *testConfig) GetDataDir() string      { return c.dir }
+func (c *testConfig) GetSystemCode() string   { return "TEST" }
+func (c *testConfig) GetStoreBackend() string { return c.backend }
+func (c *testConfig) GetCacheSize() int64     { return c.cacheSize }
+
+func (c *testConfig) GetMetricsRegistry() *metrics.Registry { return c.registry }
+
+func (c *testConfig) GetEncryptionKeys() string    { return c.keys }
+func (c *testConfig) GetEncryptionKeyFile() string { return "" }
+func (c *testConfig) GetEncryptKeys() bool         { return c.encryptKeys }
+
+func (c *testConfig) GetPipelineDepth() int { return c.depth }
+
+func newTestService(t *testing.T, c *testConfig) *MerkleService {
+	if len(c.dir) == 0 {
+		c.dir = t.TempDir()
+	}
+	if len(c.backend) == 0 {
+		c.backend = store.MEMORY
+	}
+	service, err := NewMerkleService(c, &crypto.CryptoService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { service.Close() })
+	return service.(*MerkleService)
+}
+
+func TestCachedStateCopies(t *testing.T) {
+	service := newTestService(t, &testConfig{cacheSize: 1 << 20})
+
+	alice := withValue(t, testAccountState(t, "alice"), "100")
+	err := service.PutState(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := alice.GetHash()
+
+	for i := 0; i < 2; i++ {
+		s, err := service.GetStateByHash(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a := s.(*block.AccountState)
+		if a.Amount.Value.String() != "100" {
+			t.Fatalf("read %d: got %s, want 100", i, a.Amount.Value.String())
+		}
+		*a = *withValue(t, a, "1")
+	}
+
+	err = service.PutBlock(&block.Block{BlockIndex: 0, States: []libblock.State{alice}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = service.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, hash, err := service.GetHead()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		header, err := service.GetHeaderByHash(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header.BlockIndex != 0 {
+			t.Fatalf("read %d: got block %d, want 0", i, header.BlockIndex)
+		}
+		header.BlockIndex = 1
+	}
+}
+
+func TestServiceMetrics(t *testing.T) {
+	r := metrics.NewRegistry()
+	a := newTestService(t, &testConfig{registry: r})
+	b := newTestService(t, &testConfig{registry: r})
+	for _, service := range []*MerkleService{a, a, b} {
+		err := service.PutState(testAccountState(t, "alice"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	err := r.WriteText(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		fmt.Sprintf(`merkle_puts_total{service=%q,kind="state"} 2`, a.config.GetDataDir()),
+		fmt.Sprintf(`merkle_puts_total{service=%q,kind="state"} 1`, b.config.GetDataDir()),
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Fatalf("missing %q in\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestBlockStorage(t *testing.T) {
+	service := newTestService(t, &testConfig{})
+
+	b := &block.Block{
+		BlockIndex: 0,
+		States:     []libblock.State{withValue(t, testAccountState(t, "alice"), "100")},
+	}
+	err := service.PutBlock(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = service.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, h, err := service.GetHead()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the header and the body are stored apart, the block is not stored whole
+	data, err := service.bm.GetData(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta := core.GetMeta(data); meta != core.CORE_BLOCK_HEADER {
+		t.Fatalf("stored block meta %d", meta)
+	}
+	body, err := service.bm.GetData(getBodyKey(h))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta := core.GetMeta(body); meta != core.CORE_BLOCK_BODY {
+		t.Fatalf("stored body meta %d", meta)
+	}
+
+	lazy, err := service.GetBlockByHash(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = block.LoadBody(lazy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lazy.GetStates()) != 1 {
+		t.Fatalf("loaded %d states", len(lazy.GetStates()))
+	}
+
+	err = service.bm.PutData(getBodyKey(h), body[:len(body)/2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	lazy, err = service.GetBlockByHash(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.LoadBody(lazy) == nil {
+		t.Fatal("loaded a broken body")
+	}
+}
+
+// failingStore fails every read once broken.
+type failingStore struct {
+	*store.MemoryService
+	broken bool
+}
+
+func (s *failingStore) GetData(key []byte) ([]byte, error) {
+	if s.broken {
+		return nil, errors.New("disk failure")
+	}
+	return s.MemoryService.GetData(key)
+}
+
+func TestStoreErrors(t *testing.T) {
+	kv := &failingStore{MemoryService: &store.MemoryService{}}
+	kv.Init(nil)
+	tree := NewMerkleTree(&crypto.CryptoService{}, kv)
+	err := tree.PutData([]byte("key"), []byte("value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tree.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the nodes of a reopened tree are read from the store
+	tree = NewMerkleTree(&crypto.CryptoService{}, kv)
+	_, err = tree.GetData([]byte("missing"))
+	if !IsNonexists(err) {
+		t.Errorf("missing key returned %v", err)
+	}
+	kv.broken = true
+	_, err = tree.GetData([]byte("key"))
+	if err == nil || IsNonexists(err) {
+		t.Errorf("store failure returned %v", err)
+	}
+}
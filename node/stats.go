@@ -0,0 +1,52 @@
+package node
+
+import (
+	"fmt"
+
+	"github.com/tokentransfer/chain/store"
+)
+
+// GetStoreStats returns the size statistics of every store which reports
+// them, keyed by store name.
+func (service *MerkleService) GetStoreStats() (map[string]*store.StoreStats, error) {
+	stats := make(map[string]*store.StoreStats)
+	for i, t := range service.trees() {
+		s, ok := store.Unwrap(t.ss).(store.Statter)
+		if !ok {
+			continue
+		}
+		st, err := s.Stats()
+		if err != nil {
+			return nil, err
+		}
+		stats[treeNames()[i]] = st
+	}
+	return stats, nil
+}
+
+// Compact compacts the keys with the prefix in the named store, or in every
+// store if the name is empty.
+func (service *MerkleService) Compact(name string, prefix []byte) error {
+	found := false
+	for i, t := range service.trees() {
+		if len(name) > 0 && name != treeNames()[i] {
+			continue
+		}
+		found = true
+		c, ok := store.Unwrap(t.ss).(store.Compacter)
+		if !ok {
+			if len(name) > 0 {
+				return fmt.Errorf("store %s doesn't support compaction", name)
+			}
+			continue
+		}
+		err := c.Compact(prefix)
+		if err != nil {
+			return err
+		}
+	}
+	if !found {
+		return ErrorOfNonexists("store", name)
+	}
+	return nil
+}
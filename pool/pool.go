@@ -0,0 +1,232 @@
+package pool
+
+import (
+	"container/heap"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/tokentransfer/chain/block"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+)
+
+var (
+	ErrBadTransaction = errors.New("bad transaction")
+	ErrBadSignature   = errors.New("bad signature")
+	ErrUnderpriced    = errors.New("gas below the base fee")
+	ErrReplaced       = errors.New("transaction already pending with more gas")
+	ErrFull           = errors.New("pool is full")
+)
+
+// Authorizer checks the signatures of a transaction, and that their keys may
+// sign for its account.
+type Authorizer interface {
+	Authorize(tx *block.Transaction) bool
+}
+
+type entry struct {
+	tx  *block.Transaction
+	seq uint64 // arrival order
+}
+
+// Pool keeps the transactions waiting for a block. Transactions whose Gas is
+// below the base fee of the next block are refused, and are kept out of the
+// blocks while the base fee stays above it.
+type Pool struct {
+	lock     sync.Mutex
+	auth     Authorizer
+	size     int
+	baseFee  int64
+	arrivals uint64
+	accounts map[string][]*entry // pending transactions by account, in sequence order
+	count    int
+}
+
+// NewPool returns a pool holding at most size transactions, or any number if
+// size is 0, whose transactions are checked by auth.
+func NewPool(auth Authorizer, size int) *Pool {
+	return &Pool{
+		auth:     auth,
+		size:     size,
+		accounts: make(map[string][]*entry),
+	}
+}
+
+func (p *Pool) GetBaseFee() int64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.baseFee
+}
+
+// SetBaseFee sets the base fee of the next block. The transactions whose Gas
+// is below it are kept, but aren't pending until it falls.
+func (p *Pool) SetBaseFee(fee int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.baseFee = fee
+}
+
+// Add checks the signatures and gas of the transaction and keeps it until it
+// is removed. A pending transaction with the same account and sequence is
+// replaced only by one with more gas, whose keys may sign for the account.
+func (p *Pool) Add(tx libblock.Transaction) error {
+	t, ok := tx.(*block.Transaction)
+	if !ok || t.Account == nil {
+		return ErrBadTransaction
+	}
+	if !p.auth.Authorize(t) {
+		return ErrBadSignature
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if t.Gas < p.baseFee {
+		return ErrUnderpriced
+	}
+	key := t.Account.String()
+	list := p.accounts[key]
+	i := sort.Search(len(list), func(i int) bool {
+		return list[i].tx.Sequence >= t.Sequence
+	})
+	p.arrivals++
+	e := &entry{tx: t, seq: p.arrivals}
+	if i < len(list) && list[i].tx.Sequence == t.Sequence {
+		if t.Gas <= list[i].tx.Gas {
+			return ErrReplaced
+		}
+		list[i] = e
+		return nil
+	}
+	if p.size > 0 && p.count >= p.size {
+		return ErrFull
+	}
+	list = append(list, nil)
+	copy(list[i+1:], list[i:])
+	list[i] = e
+	p.accounts[key] = list
+	p.count++
+	return nil
+}
+
+// Remove drops the transactions, and the pending transactions of their
+// accounts with a lower sequence, once they are in a block.
+func (p *Pool) Remove(txs []libblock.Transaction) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, tx := range txs {
+		t, ok := tx.(*block.Transaction)
+		if !ok || t.Account == nil {
+			continue
+		}
+		key := t.Account.String()
+		list := p.accounts[key]
+		i := sort.Search(len(list), func(i int) bool {
+			return list[i].tx.Sequence > t.Sequence
+		})
+		p.count -= i
+		if i == len(list) {
+			delete(p.accounts, key)
+		} else {
+			p.accounts[key] = list[i:]
+		}
+	}
+}
+
+// Drop removes transactions which can never be applied. The transactions of
+// their account which follow them are kept, as another transaction may take
+// the sequence.
+func (p *Pool) Drop(txs []libblock.Transaction) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, tx := range txs {
+		t, ok := tx.(*block.Transaction)
+		if !ok || t.Account == nil {
+			continue
+		}
+		key := t.Account.String()
+		list := p.accounts[key]
+		i := sort.Search(len(list), func(i int) bool {
+			return list[i].tx.Sequence >= t.Sequence
+		})
+		if i == len(list) || list[i].tx != t {
+			continue
+		}
+		p.count--
+		if len(list) == 1 {
+			delete(p.accounts, key)
+		} else {
+			p.accounts[key] = append(list[:i:i], list[i+1:]...)
+		}
+	}
+}
+
+func (p *Pool) Len() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.count
+}
+
+// Pending returns at most max transactions for the next block, or all of
+// them if max is 0. The transactions with the most gas above the base fee
+// come first, but the transactions of an account keep their sequence order.
+// A transaction whose Gas is below the base fee holds back the transactions
+// of its account which follow it.
+func (p *Pool) Pending(max int) []libblock.Transaction {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	h := &queue{}
+	for _, list := range p.accounts {
+		if list[0].tx.Gas >= p.baseFee {
+			heap.Push(h, list)
+		}
+	}
+	txs := make([]libblock.Transaction, 0)
+	for h.Len() > 0 && (max <= 0 || len(txs) < max) {
+		list := heap.Pop(h).([]*entry)
+		txs = append(txs, list[0].tx)
+		if len(list) > 1 && list[1].tx.Gas >= p.baseFee {
+			heap.Push(h, list[1:])
+		}
+	}
+	return txs
+}
+
+// queue orders the accounts by the gas of their next transaction, then by
+// its arrival.
+type queue [][]*entry
+
+func (q queue) Len() int {
+	return len(q)
+}
+
+func (q queue) Less(i, j int) bool {
+	a, b := q[i][0], q[j][0]
+	if a.tx.Gas != b.tx.Gas {
+		return a.tx.Gas > b.tx.Gas
+	}
+	return a.seq < b.seq
+}
+
+func (q queue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *queue) Push(x interface{}) {
+	*q = append(*q, x.([]*entry))
+}
+
+func (q *queue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	*q = old[:n-1]
+	return x
+}
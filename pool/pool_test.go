@@ -0,0 +1,120 @@
+package pool
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tokentransfer/chain/account/jingtum"
+	"github.com/tokentransfer/chain/block"
+	"github.com/tokentransfer/chain/crypto"
+
+	libblock "github.com/tokentransfer/interfaces/block"
+)
+
+func signed(t *testing.T, password string, sequence uint64, gas int64) *block.Transaction {
+	key, err := jingtum.GenerateFamilySeed(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := key.GetAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &block.Transaction{
+		TransactionType: block.TRANSACTION,
+		Account:         address,
+		Sequence:        sequence,
+		Gas:             gas,
+		Destination:     address,
+	}
+	err = (&crypto.CryptoService{}).Sign(key, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// signatures authorizes the transactions signed by the master key of their
+// account.
+type signatures struct{}
+
+func (signatures) Authorize(tx *block.Transaction) bool {
+	ok, err := (&crypto.CryptoService{}).Verify(tx)
+	return err == nil && ok
+}
+
+func describe(p *Pool) string {
+	list := make([]string, 0)
+	for _, tx := range p.Pending(0) {
+		t := tx.(*block.Transaction)
+		list = append(list, fmt.Sprintf("%d/%d", t.Sequence, t.Gas))
+	}
+	return fmt.Sprint(list)
+}
+
+func TestPool(t *testing.T) {
+	p := NewPool(signatures{}, 0)
+	p.SetBaseFee(10)
+
+	if err := p.Add(signed(t, "alice", 1, 9)); err != ErrUnderpriced {
+		t.Errorf("added underpriced transaction: %v", err)
+	}
+	tampered := signed(t, "alice", 1, 20)
+	tampered.Gas = 30
+	if err := p.Add(tampered); err != ErrBadSignature {
+		t.Errorf("added tampered transaction: %v", err)
+	}
+
+	for _, tx := range []*block.Transaction{
+		signed(t, "alice", 2, 50),
+		signed(t, "alice", 1, 20),
+		signed(t, "bob", 1, 30),
+		signed(t, "carol", 1, 10),
+		signed(t, "carol", 2, 40),
+	} {
+		if err := p.Add(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the transactions of an account stay in sequence order
+	if s := describe(p); s != "[1/30 1/20 2/50 1/10 2/40]" {
+		t.Errorf("pending %s", s)
+	}
+
+	if err := p.Add(signed(t, "bob", 1, 30)); err != ErrReplaced {
+		t.Errorf("replaced with same gas: %v", err)
+	}
+	if err := p.Add(signed(t, "carol", 1, 35)); err != nil {
+		t.Fatal(err)
+	}
+	if s := describe(p); s != "[1/35 2/40 1/30 1/20 2/50]" {
+		t.Errorf("pending after replacement %s", s)
+	}
+
+	// the transactions under the base fee are held back, not dropped
+	p.SetBaseFee(25)
+	if s := describe(p); s != "[1/35 2/40 1/30]" || p.Len() != 5 {
+		t.Errorf("pending after base fee %s", s)
+	}
+
+	p.Remove(p.Pending(1))
+	if s := describe(p); s != "[2/40 1/30]" || p.Len() != 4 {
+		t.Errorf("pending after remove %s", s)
+	}
+
+	p.Drop(p.Pending(1))
+	if s := describe(p); s != "[1/30]" || p.Len() != 3 {
+		t.Errorf("pending after drop %s", s)
+	}
+
+	p.SetBaseFee(10)
+	if s := describe(p); s != "[1/30 1/20 2/50]" {
+		t.Errorf("pending after the base fee fell %s", s)
+	}
+
+	// the transactions which follow a dropped one are kept
+	p.Drop([]libblock.Transaction{p.Pending(0)[1]})
+	if s := describe(p); s != "[2/50 1/30]" || p.Len() != 2 {
+		t.Errorf("pending after drop %s", s)
+	}
+}
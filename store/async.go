@@ -0,0 +1,309 @@
+package store
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/tokentransfer/interfaces/core"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+// PipelineConfig is implemented by configs which commit blocks in the
+// background. The depth is the number of commits which may wait to be
+// written before a new commit blocks.
+type PipelineConfig interface {
+	GetPipelineDepth() int
+}
+
+// GetPipelineDepth returns the pipeline depth selected by the config, or 0 if
+// commits are written synchronously.
+func GetPipelineDepth(c core.Config) int {
+	if pc, ok := c.(PipelineConfig); ok {
+		return pc.GetPipelineDepth()
+	}
+	return 0
+}
+
+type asyncLayer struct {
+	seq    uint64
+	keys   [][]byte
+	values map[string][]byte
+}
+
+// AsyncService writes to another KvService in the background. Writes are
+// buffered in a layer until Seal, which queues the layer for the background
+// writer. Reads see the buffered and queued layers, newest first, so they
+// never wait for the writer.
+type AsyncService struct {
+	libstore.KvService
+
+	lock    sync.Mutex
+	cond    *sync.Cond
+	current *asyncLayer
+	queue   []*asyncLayer // sealed layers, oldest first
+	depth   int
+	sealed  uint64 // sequence of the last sealed layer
+	written uint64 // sequence of the last written layer
+	err     error  // first error of the background writer
+	closed  bool
+	done    chan struct{}
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewAsyncService starts the background writer. Seal blocks while depth
+// layers are waiting to be written.
+func NewAsyncService(service libstore.KvService, depth int) *AsyncService {
+	if depth < 1 {
+		depth = 1
+	}
+	s := &AsyncService{
+		KvService: service,
+		depth:     depth,
+		done:      make(chan struct{}),
+	}
+	s.cond = sync.NewCond(&s.lock)
+	s.current = s.newLayer()
+	go s.run()
+	return s
+}
+
+func (service *AsyncService) newLayer() *asyncLayer {
+	return &asyncLayer{
+		seq:    service.sealed + 1,
+		values: make(map[string][]byte),
+	}
+}
+
+func (service *AsyncService) Unwrap() libstore.KvService {
+	return service.KvService
+}
+
+func (service *AsyncService) run() {
+	defer close(service.done)
+
+	service.lock.Lock()
+	defer service.lock.Unlock()
+	for {
+		for len(service.queue) == 0 && !service.closed {
+			service.cond.Wait()
+		}
+		if len(service.queue) == 0 {
+			return
+		}
+		l := service.queue[0]
+		service.lock.Unlock()
+		err := service.write(l)
+		service.lock.Lock()
+
+		if err != nil && service.err == nil {
+			service.err = err
+		}
+		// the layer is dropped even if the write failed, as the error is
+		// returned to every later caller
+		service.queue = service.queue[1:]
+		service.written = l.seq
+		service.cond.Broadcast()
+	}
+}
+
+func (service *AsyncService) write(l *asyncLayer) error {
+	if len(l.keys) == 0 {
+		return nil
+	}
+	values := make([][]byte, len(l.keys))
+	for i, key := range l.keys {
+		values[i] = l.values[string(key)]
+	}
+	err := service.KvService.PutDatas(l.keys, values)
+	if err != nil {
+		return err
+	}
+	return service.KvService.Flush()
+}
+
+// Seal queues the buffered writes for the background writer and returns the
+// sequence to pass to Wait. It blocks while the queue is full.
+func (service *AsyncService) Seal() (uint64, error) {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	for len(service.queue) >= service.depth && service.err == nil && !service.closed {
+		service.cond.Wait()
+	}
+	if service.err != nil {
+		return 0, service.err
+	}
+	if service.closed {
+		return 0, errors.New("closed")
+	}
+	l := service.current
+	service.queue = append(service.queue, l)
+	service.sealed = l.seq
+	service.current = service.newLayer()
+	service.cond.Broadcast()
+	return l.seq, nil
+}
+
+// Wait blocks until the layer with the sequence is written, and returns the
+// error of the background writer if any.
+func (service *AsyncService) Wait(seq uint64) error {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	for service.written < seq && service.err == nil {
+		service.cond.Wait()
+	}
+	return service.err
+}
+
+// Sync seals the buffered writes and waits until they are written.
+func (service *AsyncService) Sync() error {
+	seq, err := service.Seal()
+	if err != nil {
+		return err
+	}
+	return service.Wait(seq)
+}
+
+// Written returns the sequence of the last written layer.
+func (service *AsyncService) Written() uint64 {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	return service.written
+}
+
+// Sealed returns the sequence of the last sealed layer.
+func (service *AsyncService) Sealed() uint64 {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	return service.sealed
+}
+
+// Pending returns the number of sealed layers not written yet.
+func (service *AsyncService) Pending() int {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	return len(service.queue)
+}
+
+func (service *AsyncService) PutData(key []byte, value []byte) error {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	if service.err != nil {
+		return service.err
+	}
+	l := service.current
+	s := string(key)
+	if _, ok := l.values[s]; !ok {
+		l.keys = append(l.keys, append([]byte(nil), key...))
+	}
+	l.values[s] = append([]byte(nil), value...)
+	return nil
+}
+
+func (service *AsyncService) PutDatas(keys [][]byte, values [][]byte) error {
+	if len(keys) != len(values) {
+		return errors.New("length error")
+	}
+	for i := range keys {
+		err := service.PutData(keys[i], values[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup returns the value of the key in the newest layer which has it.
+func (service *AsyncService) lookup(key []byte) ([]byte, bool) {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	s := string(key)
+	if value, ok := service.current.values[s]; ok {
+		return value, true
+	}
+	for i := len(service.queue) - 1; i >= 0; i-- {
+		if value, ok := service.queue[i].values[s]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func (service *AsyncService) GetData(key []byte) ([]byte, error) {
+	if value, ok := service.lookup(key); ok {
+		return append([]byte(nil), value...), nil
+	}
+	return service.KvService.GetData(key)
+}
+
+func (service *AsyncService) GetDatas(keys [][]byte) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		value, err := service.GetData(key)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func (service *AsyncService) HasData(key []byte) bool {
+	if value, ok := service.lookup(key); ok {
+		return len(value) > 0
+	}
+	return service.KvService.HasData(key)
+}
+
+func (service *AsyncService) RemoveData(key []byte) error {
+	err := service.Sync()
+	if err != nil {
+		return err
+	}
+	return service.KvService.RemoveData(key)
+}
+
+func (service *AsyncService) ListData(each func(key []byte, value []byte) error) error {
+	err := service.Sync()
+	if err != nil {
+		return err
+	}
+	return service.KvService.ListData(each)
+}
+
+// Flush does nothing, as the layers are written by the background writer.
+// Use Sync to wait until the writes are on disk.
+func (service *AsyncService) Flush() error {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	return service.err
+}
+
+// Close writes the buffered changes and closes the underlying service. Only
+// the first call does so, later calls return its error.
+func (service *AsyncService) Close() error {
+	service.closeOnce.Do(func() {
+		err := service.Sync()
+
+		service.lock.Lock()
+		service.closed = true
+		service.cond.Broadcast()
+		service.lock.Unlock()
+		<-service.done
+
+		service.closeErr = service.KvService.Close()
+		if err != nil {
+			service.closeErr = err
+		}
+	})
+	return service.closeErr
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/tokentransfer/chain/store/storetest"
+
+	. "github.com/tokentransfer/check"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+type AsyncSuite struct{}
+
+func Test_Async(t *testing.T) {
+	s := Suite(&AsyncSuite{})
+	TestingRun(t, s)
+}
+
+func (suite *AsyncSuite) TestConformance(c *C) {
+	storetest.Run(c, func() libstore.KvService {
+		service, err := NewKvService(MEMORY, "test", nil)
+		c.Assert(err, IsNil)
+		return NewAsyncService(service, 2)
+	})
+}
+
+// gatedService signals every write it starts, then blocks it until the gate
+// is released.
+type gatedService struct {
+	libstore.KvService
+
+	writing chan struct{}
+	gate    chan struct{}
+}
+
+func (service *gatedService) PutDatas(keys [][]byte, values [][]byte) error {
+	service.writing <- struct{}{}
+	<-service.gate
+	return service.KvService.PutDatas(keys, values)
+}
+
+func (suite *AsyncSuite) TestPipeline(c *C) {
+	memory, err := NewKvService(MEMORY, "test", nil)
+	c.Assert(err, IsNil)
+	gated := &gatedService{memory, make(chan struct{}), make(chan struct{})}
+	service := NewAsyncService(gated, 1)
+
+	err = service.PutData([]byte("a"), []byte("1"))
+	c.Assert(err, IsNil)
+	first, err := service.Seal()
+	c.Assert(err, IsNil)
+	<-gated.writing
+
+	// the sealed layer is readable before it is written
+	value, err := service.GetData([]byte("a"))
+	c.Assert(err, IsNil)
+	c.Assert(string(value), Equals, "1")
+	c.Assert(memory.HasData([]byte("a")), Equals, false)
+
+	err = service.PutData([]byte("b"), []byte("2"))
+	c.Assert(err, IsNil)
+	// the pipeline is full, so the seal waits until the first layer is
+	// written
+	type result struct {
+		seq     uint64
+		written bool
+	}
+	sealed := make(chan result)
+	go func() {
+		seq, _ := service.Seal()
+		sealed <- result{seq, memory.HasData([]byte("a"))}
+	}()
+
+	gated.gate <- struct{}{}
+	err = service.Wait(first)
+	c.Assert(err, IsNil)
+	c.Assert(memory.HasData([]byte("a")), Equals, true)
+
+	second := <-sealed
+	c.Assert(second.written, Equals, true)
+	<-gated.writing
+	gated.gate <- struct{}{}
+	err = service.Wait(second.seq)
+	c.Assert(err, IsNil)
+	c.Assert(service.Written(), Equals, second.seq)
+	c.Assert(memory.HasData([]byte("b")), Equals, true)
+
+	err = service.Close()
+	c.Assert(err, IsNil)
+	err = service.Close()
+	c.Assert(err, IsNil)
+}
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"errors"
+
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+// BatchService buffers the writes to another KvService in memory and writes
+// them with a single PutDatas on Flush. Reads see the buffered writes.
+type BatchService struct {
+	libstore.KvService
+
+	keys   [][]byte
+	values map[string][]byte
+	size   int
+}
+
+func NewBatchService(service libstore.KvService) *BatchService {
+	return &BatchService{
+		KvService: service,
+		values:    make(map[string][]byte),
+	}
+}
+
+func (service *BatchService) Unwrap() libstore.KvService {
+	return service.KvService
+}
+
+// Size returns the number of bytes buffered.
+func (service *BatchService) Size() int {
+	return service.size
+}
+
+func (service *BatchService) PutData(key []byte, value []byte) error {
+	s := string(key)
+	if old, ok := service.values[s]; ok {
+		service.size -= len(key) + len(old)
+	} else {
+		service.keys = append(service.keys, append([]byte(nil), key...))
+	}
+	service.values[s] = append([]byte(nil), value...)
+	service.size += len(key) + len(value)
+	return nil
+}
+
+func (service *BatchService) PutDatas(keys [][]byte, values [][]byte) error {
+	if len(keys) != len(values) {
+		return errors.New("length error")
+	}
+	for i := range keys {
+		err := service.PutData(keys[i], values[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *BatchService) GetData(key []byte) ([]byte, error) {
+	if value, ok := service.values[string(key)]; ok {
+		return append([]byte(nil), value...), nil
+	}
+	return service.KvService.GetData(key)
+}
+
+func (service *BatchService) GetDatas(keys [][]byte) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		value, err := service.GetData(key)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func (service *BatchService) HasData(key []byte) bool {
+	if value, ok := service.values[string(key)]; ok {
+		return len(value) > 0
+	}
+	return service.KvService.HasData(key)
+}
+
+func (service *BatchService) RemoveData(key []byte) error {
+	err := service.Flush()
+	if err != nil {
+		return err
+	}
+	return service.KvService.RemoveData(key)
+}
+
+func (service *BatchService) ListData(each func(key []byte, value []byte) error) error {
+	err := service.Flush()
+	if err != nil {
+		return err
+	}
+	return service.KvService.ListData(each)
+}
+
+// Flush writes the buffered entries to the underlying service.
+func (service *BatchService) Flush() error {
+	if len(service.keys) > 0 {
+		values := make([][]byte, len(service.keys))
+		for i, key := range service.keys {
+			values[i] = service.values[string(key)]
+		}
+		err := service.KvService.PutDatas(service.keys, values)
+		if err != nil {
+			return err
+		}
+		service.keys = nil
+		service.values = make(map[string][]byte)
+		service.size = 0
+	}
+	return service.KvService.Flush()
+}
+
+func (service *BatchService) Close() error {
+	err := service.Flush()
+	if err != nil {
+		return err
+	}
+	return service.KvService.Close()
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/tokentransfer/interfaces/core"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+// CacheConfig is implemented by configs which enable read caching. The size
+// is the maximum number of bytes kept in memory for each cache.
+type CacheConfig interface {
+	GetCacheSize() int64
+}
+
+// GetCacheSize returns the cache size selected by the config, or 0 if caching
+// is disabled.
+func GetCacheSize(c core.Config) int64 {
+	if cc, ok := c.(CacheConfig); ok {
+		return cc.GetCacheSize()
+	}
+	return 0
+}
+
+// CacheService is a read-through cache in front of another KvService. Writes
+// go straight to the underlying service and invalidate the cached entry.
+// Every write bumps a generation, and a read only fills the cache if no
+// write happened since it started, so a value read before a concurrent write
+// can't be put back after the write invalidated it.
+type CacheService struct {
+	libstore.KvService
+
+	cache *LRU
+
+	mu         sync.Mutex
+	generation uint64
+}
+
+func NewCacheService(service libstore.KvService, maxSize int64) *CacheService {
+	return &CacheService{
+		KvService: service,
+		cache:     NewLRU(maxSize),
+	}
+}
+
+func (service *CacheService) Unwrap() libstore.KvService {
+	return service.KvService
+}
+
+func (service *CacheService) Stats() CacheStats {
+	return service.cache.Stats()
+}
+
+// invalidate removes the keys from the cache once they were written.
+func (service *CacheService) invalidate(keys ...[]byte) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	service.generation++
+	for _, key := range keys {
+		service.cache.Remove(string(key))
+	}
+}
+
+func (service *CacheService) PutData(key []byte, value []byte) error {
+	err := service.KvService.PutData(key, value)
+	service.invalidate(key)
+	return err
+}
+
+func (service *CacheService) PutDatas(keys [][]byte, values [][]byte) error {
+	err := service.KvService.PutDatas(keys, values)
+	service.invalidate(keys...)
+	return err
+}
+
+func (service *CacheService) GetData(key []byte) ([]byte, error) {
+	s := string(key)
+	if value, ok := service.cache.Get(s); ok {
+		return append([]byte(nil), value.([]byte)...), nil
+	}
+	service.mu.Lock()
+	generation := service.generation
+	service.mu.Unlock()
+
+	value, err := service.KvService.GetData(key)
+	if err != nil {
+		return nil, err
+	}
+	if value != nil {
+		service.mu.Lock()
+		if service.generation == generation {
+			service.cache.Add(s, append([]byte(nil), value...), int64(len(key)+len(value)))
+		}
+		service.mu.Unlock()
+	}
+	return value, nil
+}
+
+func (service *CacheService) GetDatas(keys [][]byte) ([][]byte, error) {
+	l := len(keys)
+	values := make([][]byte, l)
+	for i := 0; i < l; i++ {
+		value, err := service.GetData(keys[i])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func (service *CacheService) HasData(key []byte) bool {
+	if value, ok := service.cache.Get(string(key)); ok {
+		return len(value.([]byte)) > 0
+	}
+	return service.KvService.HasData(key)
+}
+
+func (service *CacheService) RemoveData(key []byte) error {
+	err := service.KvService.RemoveData(key)
+	service.invalidate(key)
+	return err
+}
+
+func (service *CacheService) Close() error {
+	service.cache.Purge()
+	return service.KvService.Close()
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/tokentransfer/chain/store/storetest"
+
+	. "github.com/tokentransfer/check"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+type CacheSuite struct{}
+
+func Test_Cache(t *testing.T) {
+	s := Suite(&CacheSuite{})
+	TestingRun(t, s)
+}
+
+func (suite *CacheSuite) TestConformance(c *C) {
+	storetest.Run(c, func() libstore.KvService {
+		service, err := NewKvService(MEMORY, "test", nil)
+		c.Assert(err, IsNil)
+		return NewCacheService(service, 1024)
+	})
+}
+
+func (suite *CacheSuite) TestLRU(c *C) {
+	cache := NewLRU(10)
+	cache.Add("a", 1, 4)
+	cache.Add("b", 2, 4)
+	_, ok := cache.Get("a")
+	c.Assert(ok, Equals, true)
+
+	cache.Add("c", 3, 4)
+	_, ok = cache.Get("b")
+	c.Assert(ok, Equals, false)
+	_, ok = cache.Get("a")
+	c.Assert(ok, Equals, true)
+
+	cache.Add("d", 4, 11)
+	_, ok = cache.Get("d")
+	c.Assert(ok, Equals, false)
+
+	stats := cache.Stats()
+	c.Assert(stats.Hits, Equals, uint64(2))
+	c.Assert(stats.Misses, Equals, uint64(2))
+	c.Assert(stats.Count, Equals, 2)
+	c.Assert(stats.Size, Equals, int64(8))
+}
+
+func (suite *CacheSuite) TestInvalidation(c *C) {
+	db, err := NewKvService(MEMORY, "test", nil)
+	c.Assert(err, IsNil)
+	service := NewCacheService(db, 1024)
+
+	err = service.PutData([]byte("key"), []byte("1"))
+	c.Assert(err, IsNil)
+	value, err := service.GetData([]byte("key"))
+	c.Assert(err, IsNil)
+	c.Assert(string(value), Equals, "1")
+	value, err = service.GetData([]byte("key"))
+	c.Assert(err, IsNil)
+	c.Assert(string(value), Equals, "1")
+
+	err = service.PutData([]byte("key"), []byte("2"))
+	c.Assert(err, IsNil)
+	value, err = service.GetData([]byte("key"))
+	c.Assert(err, IsNil)
+	c.Assert(string(value), Equals, "2")
+
+	stats := service.Stats()
+	c.Assert(stats.Hits, Equals, uint64(1))
+	c.Assert(stats.Misses, Equals, uint64(2))
+}
+
+// blockingService blocks the next GetData until release is closed.
+type blockingService struct {
+	libstore.KvService
+
+	reading chan struct{}
+	release chan struct{}
+}
+
+func (service *blockingService) GetData(key []byte) ([]byte, error) {
+	value, err := service.KvService.GetData(key)
+	if service.reading != nil {
+		close(service.reading)
+		service.reading = nil
+		<-service.release
+	}
+	return value, err
+}
+
+func (suite *CacheSuite) TestStaleFill(c *C) {
+	db, err := NewKvService(MEMORY, "test", nil)
+	c.Assert(err, IsNil)
+	err = db.PutData([]byte("key"), []byte("1"))
+	c.Assert(err, IsNil)
+
+	blocking := &blockingService{
+		KvService: db,
+		reading:   make(chan struct{}),
+		release:   make(chan struct{}),
+	}
+	reading := blocking.reading
+	service := NewCacheService(blocking, 1024)
+
+	done := make(chan []byte)
+	go func() {
+		value, _ := service.GetData([]byte("key"))
+		done <- value
+	}()
+	<-reading
+	err = service.PutData([]byte("key"), []byte("2"))
+	c.Assert(err, IsNil)
+	close(blocking.release)
+	c.Assert(string(<-done), Equals, "1")
+
+	value, err := service.GetData([]byte("key"))
+	c.Assert(err, IsNil)
+	c.Assert(string(value), Equals, "2")
+}
@@ -0,0 +1,671 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tokentransfer/interfaces/core"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+// EncryptionConfig is implemented by configs which encrypt stored data. The
+// keys are given inline or in a key file, in the format read by ParseKeyring.
+type EncryptionConfig interface {
+	GetEncryptionKeys() string
+	GetEncryptionKeyFile() string
+	GetEncryptKeys() bool
+}
+
+// GetEncryptKeys reports whether the config encrypts the keys as well as the
+// values.
+func GetEncryptKeys(c core.Config) bool {
+	if ec, ok := c.(EncryptionConfig); ok {
+		return ec.GetEncryptKeys()
+	}
+	return false
+}
+
+// GetKeyring returns the keyring selected by the config, or nil if
+// encryption is disabled.
+func GetKeyring(c core.Config) (*Keyring, error) {
+	ec, ok := c.(EncryptionConfig)
+	if !ok {
+		return nil, nil
+	}
+	if f := ec.GetEncryptionKeyFile(); len(f) > 0 {
+		return LoadKeyring(f)
+	}
+	if s := ec.GetEncryptionKeys(); len(s) > 0 {
+		return ParseKeyring(s)
+	}
+	return nil, nil
+}
+
+type cryptKey struct {
+	aead   cipher.AEAD
+	macKey []byte
+}
+
+// Keyring holds the AES keys by id. The active key encrypts new data in the
+// stores which were never rotated, the others are only used to read data
+// which has not been re-encrypted yet.
+type Keyring struct {
+	mu     sync.RWMutex
+	keys   map[byte]*cryptKey
+	active byte
+}
+
+// ParseKeyring reads keys as "id:hex" entries separated by commas or new
+// lines, where id is 1-255 and hex is a 16, 24 or 32 byte AES key. The last
+// entry is the active key.
+func ParseKeyring(s string) (*Keyring, error) {
+	k := &Keyring{keys: make(map[byte]*cryptKey)}
+	entries := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 {
+			return nil, ErrorOfInvalid("key entry", entry)
+		}
+		id, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 8)
+		if err != nil || id == 0 {
+			return nil, ErrorOfInvalid("key id", parts[0])
+		}
+		key, err := hex.DecodeString(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, err
+		}
+		err = k.AddKey(byte(id), key)
+		if err != nil {
+			return nil, err
+		}
+		k.active = byte(id)
+	}
+	if k.active == 0 {
+		return nil, errors.New("empty keyring")
+	}
+	return k, nil
+}
+
+func LoadKeyring(file string) (*Keyring, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeyring(string(data))
+}
+
+func (k *Keyring) AddKey(id byte, key []byte) error {
+	if id == 0 {
+		return ErrorOfInvalid("key id", "0")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("deterministic nonce"))
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys[id] = &cryptKey{aead: aead, macKey: mac.Sum(nil)}
+	return nil
+}
+
+func (k *Keyring) Active() byte {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.active
+}
+
+func (k *Keyring) SetActive(id byte) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.keys[id]; !ok {
+		return ErrorOfNonexists("key", strconv.Itoa(int(id)))
+	}
+	k.active = id
+	return nil
+}
+
+// IDs returns the ids of all keys, the active key first.
+func (k *Keyring) IDs() []byte {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	list := []byte{k.active}
+	for id := range k.keys {
+		if id != k.active {
+			list = append(list, id)
+		}
+	}
+	return list
+}
+
+func (k *Keyring) get(id byte) (*cryptKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, ErrorOfNonexists("key", strconv.Itoa(int(id)))
+	}
+	return key, nil
+}
+
+// Encrypt seals the data with the key. A deterministic encryption derives the
+// nonce from the data, so equal inputs give equal outputs and can be used as
+// lookup keys. The context is authenticated with the data, so the result only
+// opens with the same context.
+func (k *Keyring) Encrypt(id byte, data []byte, deterministic bool, context []byte) ([]byte, error) {
+	key, err := k.get(id)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, key.aead.NonceSize())
+	if deterministic {
+		mac := hmac.New(sha256.New, key.macKey)
+		mac.Write(data)
+		copy(nonce, mac.Sum(nil))
+	} else {
+		_, err = io.ReadFull(rand.Reader, nonce)
+		if err != nil {
+			return nil, err
+		}
+	}
+	out := append([]byte{id}, nonce...)
+	return key.aead.Seal(out, nonce, data, additionalData(id, context)), nil
+}
+
+// Decrypt opens data sealed by Encrypt with any key of the keyring and the
+// same context.
+func (k *Keyring) Decrypt(data []byte, context []byte) ([]byte, byte, error) {
+	if len(data) == 0 {
+		return nil, 0, errors.New("null data")
+	}
+	id := data[0]
+	key, err := k.get(id)
+	if err != nil {
+		return nil, 0, err
+	}
+	ns := key.aead.NonceSize()
+	if len(data) < 1+ns {
+		return nil, 0, errors.New("error encrypted data")
+	}
+	plain, err := key.aead.Open(nil, data[1:1+ns], data[1+ns:], additionalData(id, context))
+	if err != nil {
+		return nil, 0, err
+	}
+	return plain, id, nil
+}
+
+func additionalData(id byte, context []byte) []byte {
+	return append([]byte{id}, context...)
+}
+
+// CryptService encrypts the values, and optionally the keys, stored in
+// another KvService. Values are bound to their key, so a value moved under
+// another key doesn't decrypt. Keys are encrypted deterministically so they
+// can still be looked up, but ListData then walks them in the order of their
+// ciphertexts. The id of the key encrypting new data is kept in the wrapped
+// service, so a rotation outlives a restart.
+type CryptService struct {
+	libstore.KvService
+
+	EncryptKeys bool
+
+	keyring *Keyring
+
+	mu        sync.Mutex // serializes writes with the re-encryption
+	idMu      sync.RWMutex
+	active    byte // 0 until read from the wrapped service
+	rotMu     sync.Mutex
+	rotating  *rotation // the last one
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// activeKey holds the active key id. Encrypted keys start with a key id,
+// which is never 0.
+var activeKey = []byte("\x00crypt.active")
+
+// IsCryptMetadata reports whether a key listed from a service wrapped by a
+// CryptService holds its metadata rather than data.
+func IsCryptMetadata(k []byte) bool {
+	return string(k) == string(activeKey)
+}
+
+// rotation is a re-encryption running in the background.
+type rotation struct {
+	done chan struct{}
+	err  error // set once done is closed
+}
+
+// reencryptBatchSize is the number of entries re-encrypted at once.
+const reencryptBatchSize = 1000
+
+func NewCryptService(service libstore.KvService, keyring *Keyring, encryptKeys bool) *CryptService {
+	return &CryptService{
+		KvService:   service,
+		EncryptKeys: encryptKeys,
+		keyring:     keyring,
+		stop:        make(chan struct{}),
+	}
+}
+
+func (service *CryptService) Unwrap() libstore.KvService {
+	return service.KvService
+}
+
+// Active returns the id of the key encrypting new data: the one saved by the
+// last rotation, or the active key of the keyring.
+func (service *CryptService) Active() (byte, error) {
+	service.idMu.RLock()
+	id := service.active
+	service.idMu.RUnlock()
+	if id != 0 {
+		return id, nil
+	}
+
+	service.idMu.Lock()
+	defer service.idMu.Unlock()
+
+	if service.active != 0 {
+		return service.active, nil
+	}
+	value, err := service.KvService.GetData(activeKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(value) == 1 && value[0] != 0 {
+		service.active = value[0]
+	} else {
+		service.active = service.keyring.Active()
+	}
+	return service.active, nil
+}
+
+func (service *CryptService) setActive(id byte) error {
+	service.idMu.Lock()
+	defer service.idMu.Unlock()
+
+	err := service.KvService.PutData(activeKey, []byte{id})
+	if err != nil {
+		return err
+	}
+	service.active = id
+	return nil
+}
+
+// ids returns the ids of all keys, the active key first.
+func (service *CryptService) ids() ([]byte, error) {
+	active, err := service.Active()
+	if err != nil {
+		return nil, err
+	}
+	list := []byte{active}
+	for _, id := range service.keyring.IDs() {
+		if id != active {
+			list = append(list, id)
+		}
+	}
+	return list, nil
+}
+
+func (service *CryptService) storeKey(id byte, key []byte) ([]byte, error) {
+	if !service.EncryptKeys {
+		return key, nil
+	}
+	return service.keyring.Encrypt(id, key, true, nil)
+}
+
+// find returns the stored key and value for the key, looking under every key
+// id while a rotation is in progress.
+func (service *CryptService) find(key []byte) ([]byte, []byte, error) {
+	if !service.EncryptKeys {
+		value, err := service.KvService.GetData(key)
+		return key, value, err
+	}
+	ids, err := service.ids()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, id := range ids {
+		k, err := service.storeKey(id, key)
+		if err != nil {
+			return nil, nil, err
+		}
+		value, err := service.KvService.GetData(k)
+		if err != nil {
+			return nil, nil, err
+		}
+		if value != nil {
+			return k, value, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+func (service *CryptService) put(key []byte, value []byte) error {
+	id, err := service.Active()
+	if err != nil {
+		return err
+	}
+	k, err := service.storeKey(id, key)
+	if err != nil {
+		return err
+	}
+	v, err := service.keyring.Encrypt(id, value, false, key)
+	if err != nil {
+		return err
+	}
+	err = service.KvService.PutData(k, v)
+	if err != nil {
+		return err
+	}
+	return service.removeStale(key, id)
+}
+
+// removeStale removes the copies of the key stored under other key ids.
+func (service *CryptService) removeStale(key []byte, keep byte) error {
+	if !service.EncryptKeys {
+		return nil
+	}
+	ids, err := service.ids()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if id == keep {
+			continue
+		}
+		k, err := service.storeKey(id, key)
+		if err != nil {
+			return err
+		}
+		if service.KvService.HasData(k) {
+			err = service.KvService.RemoveData(k)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (service *CryptService) PutData(key []byte, value []byte) error {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	return service.put(key, value)
+}
+
+func (service *CryptService) PutDatas(keys [][]byte, values [][]byte) error {
+	lk := len(keys)
+	lv := len(values)
+	if lk != lv {
+		return errors.New("length error")
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	id, err := service.Active()
+	if err != nil {
+		return err
+	}
+	ks := make([][]byte, lk)
+	vs := make([][]byte, lk)
+	for i := 0; i < lk; i++ {
+		k, err := service.storeKey(id, keys[i])
+		if err != nil {
+			return err
+		}
+		v, err := service.keyring.Encrypt(id, values[i], false, keys[i])
+		if err != nil {
+			return err
+		}
+		ks[i] = k
+		vs[i] = v
+	}
+	err = service.KvService.PutDatas(ks, vs)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < lk; i++ {
+		err = service.removeStale(keys[i], id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *CryptService) GetData(key []byte) ([]byte, error) {
+	_, value, err := service.find(key)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+	plain, _, err := service.keyring.Decrypt(value, key)
+	if err != nil {
+		return nil, err
+	}
+	return plain, nil
+}
+
+func (service *CryptService) GetDatas(keys [][]byte) ([][]byte, error) {
+	l := len(keys)
+	values := make([][]byte, l)
+	for i := 0; i < l; i++ {
+		value, err := service.GetData(keys[i])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func (service *CryptService) HasData(key []byte) bool {
+	value, err := service.GetData(key)
+	if err != nil {
+		return false
+	}
+	return len(value) > 0
+}
+
+func (service *CryptService) RemoveData(key []byte) error {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	if !service.EncryptKeys {
+		return service.KvService.RemoveData(key)
+	}
+	return service.removeStale(key, 0)
+}
+
+func (service *CryptService) ListData(each func(key []byte, value []byte) error) error {
+	return service.KvService.ListData(func(k []byte, v []byte) error {
+		if IsCryptMetadata(k) {
+			return nil
+		}
+		key, value, err := service.Decode(k, v)
+		if err != nil {
+			return err
+		}
+		return each(key, value)
+	})
+}
+
+// Decode returns the plain key and value of an entry listed from the wrapped
+// service.
+func (service *CryptService) Decode(k []byte, v []byte) ([]byte, []byte, error) {
+	key := k
+	if service.EncryptKeys {
+		plain, _, err := service.keyring.Decrypt(k, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		key = plain
+	}
+	value, _, err := service.keyring.Decrypt(v, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, value, nil
+}
+
+// Rotate makes the key with the id active and re-encrypts the existing data
+// with it in the background, once the previous rotation is done.
+// WaitRotation blocks until that is done.
+func (service *CryptService) Rotate(id byte) error {
+	service.rotMu.Lock()
+	defer service.rotMu.Unlock()
+
+	if service.rotating != nil {
+		<-service.rotating.done
+	}
+	_, err := service.keyring.get(id)
+	if err != nil {
+		return err
+	}
+	err = service.setActive(id)
+	if err != nil {
+		return err
+	}
+	r := &rotation{done: make(chan struct{})}
+	service.rotating = r
+	go func() {
+		r.err = service.reencrypt(id)
+		close(r.done)
+	}()
+	return nil
+}
+
+func (service *CryptService) WaitRotation() error {
+	service.rotMu.Lock()
+	r := service.rotating
+	service.rotMu.Unlock()
+	if r == nil {
+		return nil
+	}
+	<-r.done
+	return r.err
+}
+
+var errRotationStopped = errors.New("rotation stopped")
+
+type cryptEntry struct {
+	key   []byte
+	value []byte
+}
+
+// reencrypt walks a snapshot of the wrapped service, or the service itself if
+// it can't take one, and re-encrypts the entries under other key ids in
+// batches.
+func (service *CryptService) reencrypt(id byte) error {
+	list := service.KvService.ListData
+	if s, ok := Unwrap(service.KvService).(Snapshotter); ok {
+		snapshot, err := s.Snapshot()
+		if err != nil {
+			return err
+		}
+		defer snapshot.Release()
+		list = snapshot.ListData
+	}
+
+	batch := make([]cryptEntry, 0, reencryptBatchSize)
+	err := list(func(k []byte, v []byte) error {
+		select {
+		case <-service.stop:
+			return errRotationStopped
+		default:
+		}
+		if IsCryptMetadata(k) || len(v) == 0 || v[0] == id {
+			return nil
+		}
+		batch = append(batch, cryptEntry{append([]byte(nil), k...), append([]byte(nil), v...)})
+		if len(batch) < reencryptBatchSize {
+			return nil
+		}
+		err := service.reencryptBatch(id, batch)
+		batch = batch[:0]
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return service.reencryptBatch(id, batch)
+}
+
+func (service *CryptService) reencryptBatch(id byte, batch []cryptEntry) error {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	for _, e := range batch {
+		err := service.reencryptEntry(id, e.key, e.value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *CryptService) reencryptEntry(id byte, k []byte, v []byte) error {
+	// skip the entry if it was rewritten or removed since it was listed
+	current, err := service.KvService.GetData(k)
+	if err != nil {
+		return err
+	}
+	if string(current) != string(v) {
+		return nil
+	}
+
+	key, value, err := service.Decode(k, v)
+	if err != nil {
+		return err
+	}
+	if service.EncryptKeys {
+		newKey, err := service.storeKey(id, key)
+		if err != nil {
+			return err
+		}
+		if service.KvService.HasData(newKey) {
+			return service.KvService.RemoveData(k)
+		}
+	}
+	return service.put(key, value)
+}
+
+func (service *CryptService) Close() error {
+	service.closeOnce.Do(func() {
+		close(service.stop)
+	})
+	service.WaitRotation()
+	return service.KvService.Close()
+}
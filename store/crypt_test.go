@@ -0,0 +1,139 @@
+package store
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tokentransfer/chain/store/storetest"
+
+	. "github.com/tokentransfer/check"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+type CryptSuite struct{}
+
+func Test_Crypt(t *testing.T) {
+	s := Suite(&CryptSuite{})
+	TestingRun(t, s)
+}
+
+const testKeys = "1:000102030405060708090a0b0c0d0e0f000102030405060708090a0b0c0d0e0f"
+
+func (suite *CryptSuite) TestConformance(c *C) {
+	open := func(encryptKeys bool) func() libstore.KvService {
+		return func() libstore.KvService {
+			keyring, err := ParseKeyring(testKeys)
+			c.Assert(err, IsNil)
+			db, err := NewKvService(MEMORY, "test", nil)
+			c.Assert(err, IsNil)
+			return NewCryptService(db, keyring, encryptKeys)
+		}
+	}
+	storetest.Run(c, open(false))
+	storetest.RunUnordered(c, open(true))
+}
+
+func (suite *CryptSuite) TestEncrypted(c *C) {
+	keyring, err := ParseKeyring(testKeys)
+	c.Assert(err, IsNil)
+	db, err := NewKvService(MEMORY, "test", nil)
+	c.Assert(err, IsNil)
+	service := NewCryptService(db, keyring, true)
+
+	err = service.PutData([]byte("account"), []byte("balance"))
+	c.Assert(err, IsNil)
+	err = db.ListData(func(key []byte, value []byte) error {
+		c.Assert(bytes.Contains(key, []byte("account")), Equals, false)
+		c.Assert(bytes.Contains(value, []byte("balance")), Equals, false)
+		return nil
+	})
+	c.Assert(err, IsNil)
+}
+
+func (suite *CryptSuite) TestBoundToKey(c *C) {
+	keyring, err := ParseKeyring(testKeys)
+	c.Assert(err, IsNil)
+	db, err := NewKvService(MEMORY, "test", nil)
+	c.Assert(err, IsNil)
+	service := NewCryptService(db, keyring, false)
+
+	err = service.PutData([]byte("alice"), []byte("100"))
+	c.Assert(err, IsNil)
+	sealed, err := db.GetData([]byte("alice"))
+	c.Assert(err, IsNil)
+	err = db.PutData([]byte("bob"), sealed)
+	c.Assert(err, IsNil)
+
+	_, err = service.GetData([]byte("bob"))
+	c.Assert(err, NotNil)
+	value, err := service.GetData([]byte("alice"))
+	c.Assert(err, IsNil)
+	c.Assert(string(value), Equals, "100")
+}
+
+func (suite *CryptSuite) TestRotate(c *C) {
+	keyring, err := ParseKeyring(testKeys)
+	c.Assert(err, IsNil)
+	db, err := NewKvService(MEMORY, "test", nil)
+	c.Assert(err, IsNil)
+	service := NewCryptService(db, keyring, true)
+
+	err = service.PutDatas([][]byte{[]byte("a"), []byte("b")}, [][]byte{[]byte("1"), []byte("2")})
+	c.Assert(err, IsNil)
+
+	err = keyring.AddKey(2, bytes.Repeat([]byte{7}, 32))
+	c.Assert(err, IsNil)
+	err = service.Rotate(2)
+	c.Assert(err, IsNil)
+	err = service.WaitRotation()
+	c.Assert(err, IsNil)
+
+	count := 0
+	err = db.ListData(func(key []byte, value []byte) error {
+		if IsCryptMetadata(key) {
+			return nil
+		}
+		c.Assert(key[0], Equals, byte(2))
+		c.Assert(value[0], Equals, byte(2))
+		count++
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 2)
+
+	value, err := service.GetData([]byte("b"))
+	c.Assert(err, IsNil)
+	c.Assert(string(value), Equals, "2")
+
+	// the active key is kept with the data
+	reopened := NewCryptService(db, keyring, true)
+	id, err := reopened.Active()
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, byte(2))
+	err = reopened.PutData([]byte("c"), []byte("3"))
+	c.Assert(err, IsNil)
+	keys := 0
+	err = reopened.ListData(func(key []byte, value []byte) error {
+		keys++
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(keys, Equals, 3)
+
+	// rotations run one after the other
+	err = keyring.AddKey(3, bytes.Repeat([]byte{8}, 32))
+	c.Assert(err, IsNil)
+	err = reopened.Rotate(1)
+	c.Assert(err, IsNil)
+	err = reopened.Rotate(3)
+	c.Assert(err, IsNil)
+	err = reopened.WaitRotation()
+	c.Assert(err, IsNil)
+	err = db.ListData(func(key []byte, value []byte) error {
+		if !IsCryptMetadata(key) {
+			c.Assert(value[0], Equals, byte(3))
+		}
+		return nil
+	})
+	c.Assert(err, IsNil)
+}
@@ -2,9 +2,15 @@ package store
 
 import (
 	"errors"
+	"os"
 	"path"
+	"path/filepath"
+	"strconv"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/tokentransfer/chain/metrics"
 
 	"github.com/tokentransfer/interfaces/core"
 )
@@ -15,6 +21,7 @@ type LevelService struct {
 
 	config core.Config
 	db     *leveldb.DB
+	dbPath string
 }
 
 func (service *LevelService) Close() error {
@@ -36,9 +43,11 @@ func (service *LevelService) open() error {
 		dataDir := service.config.GetDataDir()
 		dbPath := path.Join(dataDir, service.Name)
 		service.db = serviceForLevelDB(dbPath)
+		service.dbPath = dbPath
 	} else {
 		if len(service.Path) > 0 {
 			service.db = serviceForLevelDB(service.Path)
+			service.dbPath = service.Path
 		} else {
 			return errors.New("no config or path for leveldb")
 		}
@@ -109,7 +118,8 @@ func (service *LevelService) GetDatas(keys [][]byte) ([][]byte, error) {
 		value, err := db.Get(keys[i], nil)
 		if err != nil {
 			if err == leveldb.ErrNotFound {
-				return nil, nil
+				bytes[i] = nil
+				continue
 			}
 			return nil, err
 		}
@@ -151,9 +161,15 @@ func (service *LevelService) RemoveData(key []byte) error {
 }
 
 func (service *LevelService) ListData(each func(key []byte, value []byte) error) error {
+	return service.ListPrefix(nil, each)
+}
+
+// ListPrefix walks the entries whose keys start with the prefix.
+func (service *LevelService) ListPrefix(prefix []byte, each func(key []byte, value []byte) error) error {
 	db := service.db
 
-	iter := db.NewIterator(nil, nil)
+	iter := db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
 	for iter.Next() {
 		key := iter.Key()
 		value := iter.Value()
@@ -162,10 +178,192 @@ func (service *LevelService) ListData(each func(key []byte, value []byte) error)
 			return err
 		}
 	}
-	iter.Release()
 	return iter.Error()
 }
 
+func (service *LevelService) Snapshot() (Snapshot, error) {
+	return service.SnapshotPrefix(nil)
+}
+
+// SnapshotPrefix takes a snapshot of the entries whose keys start with the
+// prefix.
+func (service *LevelService) SnapshotPrefix(prefix []byte) (Snapshot, error) {
+	s, err := service.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &levelSnapshot{s, prefix}, nil
+}
+
+type levelSnapshot struct {
+	s      *leveldb.Snapshot
+	prefix []byte
+}
+
+func (snapshot *levelSnapshot) ListData(each func(key []byte, value []byte) error) error {
+	iter := snapshot.s.NewIterator(util.BytesPrefix(snapshot.prefix), nil)
+	defer iter.Release()
+	for iter.Next() {
+		err := each(iter.Key(), iter.Value())
+		if err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+func (snapshot *levelSnapshot) Release() {
+	snapshot.s.Release()
+}
+
+// Stats returns the size of the database on disk and by level, and an
+// estimate of the number of keys from the average size of the first entries.
+func (service *LevelService) Stats() (*StoreStats, error) {
+	stats := &StoreStats{}
+
+	size, err := dirSize(service.dbPath)
+	if err != nil {
+		return nil, err
+	}
+	stats.Size = size
+
+	dbStats := service.dbStats()
+	stats.LevelSizes = make([]int64, len(dbStats.LevelSizes))
+	copy(stats.LevelSizes, dbStats.LevelSizes)
+
+	sizes, err := service.db.SizeOf([]util.Range{{}})
+	if err != nil {
+		return nil, err
+	}
+	tableSize := sizes.Sum()
+
+	iter := service.db.NewIterator(nil, nil)
+	defer iter.Release()
+	count := uint64(0)
+	sampleSize := int64(0)
+	for count < statsSampleSize && iter.Next() {
+		count++
+		sampleSize += int64(len(iter.Key()) + len(iter.Value()))
+	}
+	err = iter.Error()
+	if err != nil {
+		return nil, err
+	}
+	if count < statsSampleSize {
+		stats.Keys = count
+		stats.Exact = true
+	} else {
+		average := sampleSize / int64(count)
+		if average == 0 {
+			average = 1
+		}
+		stats.Keys = uint64(tableSize / average)
+		if stats.Keys < count {
+			stats.Keys = count
+		}
+	}
+	return stats, nil
+}
+
+// Compact compacts the keys with the prefix, or the whole database if the
+// prefix is empty, so that the space of removed keys is reclaimed.
+func (service *LevelService) Compact(prefix []byte) error {
+	if len(prefix) == 0 {
+		return service.db.CompactRange(util.Range{})
+	}
+	return service.db.CompactRange(*util.BytesPrefix(prefix))
+}
+
+const statsSampleSize = 1000
+
+func dirSize(dir string) (int64, error) {
+	size := int64(0)
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+func (service *LevelService) label() string {
+	if len(service.Name) > 0 {
+		return service.Name
+	}
+	return service.Path
+}
+
+func (service *LevelService) dbStats() *leveldb.DBStats {
+	stats := &leveldb.DBStats{}
+	if service.db == nil {
+		return stats
+	}
+	err := service.db.Stats(stats)
+	if err != nil {
+		return &leveldb.DBStats{}
+	}
+	return stats
+}
+
+// RegisterMetrics exposes the internal statistics of leveldb, read when the
+// registry is collected.
+func (service *LevelService) RegisterMetrics(r *metrics.Registry) {
+	name := service.label()
+
+	r.GaugeFunc("leveldb_compactions", "Number of leveldb compactions by kind.", func() float64 {
+		return float64(service.dbStats().MemComp)
+	}, "store", name, "kind", "memory")
+	r.GaugeFunc("leveldb_compactions", "Number of leveldb compactions by kind.", func() float64 {
+		return float64(service.dbStats().Level0Comp)
+	}, "store", name, "kind", "level0")
+	r.GaugeFunc("leveldb_compactions", "Number of leveldb compactions by kind.", func() float64 {
+		return float64(service.dbStats().NonLevel0Comp)
+	}, "store", name, "kind", "nonlevel0")
+	r.GaugeFunc("leveldb_compactions", "Number of leveldb compactions by kind.", func() float64 {
+		return float64(service.dbStats().SeekComp)
+	}, "store", name, "kind", "seek")
+	r.GaugeFunc("leveldb_write_delays", "Number of writes delayed by compaction.", func() float64 {
+		return float64(service.dbStats().WriteDelayCount)
+	}, "store", name)
+	r.GaugeFunc("leveldb_io_read_bytes", "Bytes read by leveldb.", func() float64 {
+		return float64(service.dbStats().IORead)
+	}, "store", name)
+	r.GaugeFunc("leveldb_io_written_bytes", "Bytes written by leveldb.", func() float64 {
+		return float64(service.dbStats().IOWrite)
+	}, "store", name)
+	r.GaugeFunc("leveldb_opened_tables", "Number of opened leveldb tables.", func() float64 {
+		return float64(service.dbStats().OpenedTablesCount)
+	}, "store", name)
+
+	for level := 0; level < levelCount; level++ {
+		l := level
+		r.GaugeFunc("leveldb_level_size_bytes", "Size of the leveldb tables by level.", func() float64 {
+			sizes := service.dbStats().LevelSizes
+			if l < len(sizes) {
+				return float64(sizes[l])
+			}
+			return 0
+		}, "store", name, "level", strconv.Itoa(l))
+		r.GaugeFunc("leveldb_level_tables", "Number of leveldb tables by level.", func() float64 {
+			counts := service.dbStats().LevelTablesCounts
+			if l < len(counts) {
+				return float64(counts[l])
+			}
+			return 0
+		}, "store", name, "level", strconv.Itoa(l))
+	}
+}
+
+// levelCount is the number of levels of leveldb.
+const levelCount = 7
+
 func serviceForLevelDB(dbPath string) *leveldb.DB {
 	db, err := leveldb.OpenFile(dbPath, nil)
 	if err != nil {
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"container/list"
+	"sync"
+)
+
+// CacheStats reports the usage of a cache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Count  int
+	Size   int64
+}
+
+type lruEntry struct {
+	key   string
+	value interface{}
+	size  int64
+}
+
+// LRU is a least recently used cache bounded by the total size of its
+// entries, as reported by the caller on Add.
+type LRU struct {
+	MaxSize int64
+
+	mu     sync.Mutex
+	ll     *list.List
+	items  map[string]*list.Element
+	size   int64
+	hits   uint64
+	misses uint64
+}
+
+func NewLRU(maxSize int64) *LRU {
+	return &LRU{
+		MaxSize: maxSize,
+		ll:      list.New(),
+		items:   make(map[string]*list.Element),
+	}
+}
+
+func (c *LRU) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.ll.MoveToFront(e)
+	return e.Value.(*lruEntry).value, true
+}
+
+// Add inserts or replaces the entry, then evicts the least recently used
+// entries until the cache fits in MaxSize. Entries larger than MaxSize are
+// not cached.
+func (c *LRU) Add(key string, value interface{}, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		c.removeElement(e)
+	}
+	if size > c.MaxSize {
+		return
+	}
+	e := c.ll.PushFront(&lruEntry{key, value, size})
+	c.items[key] = e
+	c.size += size
+	for c.size > c.MaxSize {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+func (c *LRU) Remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		c.removeElement(e)
+	}
+}
+
+func (c *LRU) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+	c.size = 0
+}
+
+func (c *LRU) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Hits:   c.hits,
+		Misses: c.misses,
+		Count:  c.ll.Len(),
+		Size:   c.size,
+	}
+}
+
+func (c *LRU) removeElement(e *list.Element) {
+	entry := c.ll.Remove(e).(*lruEntry)
+	delete(c.items, entry.key)
+	c.size -= entry.size
+}
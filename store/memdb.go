@@ -1,81 +1,152 @@
 package store
 
 import (
-	"encoding/hex"
+	"bufio"
+	"bytes"
 	"errors"
+	"io"
+	"os"
+	"sort"
 	"sync"
 
 	"github.com/tokentransfer/interfaces/core"
 )
 
+// MemoryService keeps all data in memory. When Path is set, the data is
+// loaded from the snapshot file on Init and written back to it on Save and
+// Close, as Flush is called after every write by the wrappers.
 type MemoryService struct {
 	Name string
+	Path string
 
-	db *sync.Map
+	mu      sync.RWMutex
+	db      map[string][]byte
+	keys    []string // the keys up to sorted are in order, the new ones follow
+	sorted  int
+	removed bool // some keys aren't in db any more
 }
 
 func (service *MemoryService) Close() error {
-	return nil
+	return service.save()
 }
 
 func (service *MemoryService) Init(c core.Config) error {
-	service.db = &sync.Map{}
-	return nil
+	service.mu.Lock()
+	service.db = make(map[string][]byte)
+	service.keys = nil
+	service.sorted = 0
+	service.removed = false
+	service.mu.Unlock()
+
+	return service.load()
 }
 
 func (service *MemoryService) Start() error {
 	return nil
 }
 
+func (service *MemoryService) put(key []byte, value []byte) {
+	s := string(key)
+	if _, ok := service.db[s]; !ok {
+		service.keys = append(service.keys, s)
+	}
+	service.db[s] = append([]byte(nil), value...)
+	if len(service.keys) > 2*len(service.db)+1024 {
+		// the keys removed then put again pile up until they are sorted
+		service.sortKeys()
+	}
+}
+
+// sortKeys sorts the keys put since the last listing, and merges them with
+// the sorted ones, leaving out the removed keys.
+func (service *MemoryService) sortKeys() {
+	if service.sorted == len(service.keys) && !service.removed {
+		return
+	}
+	head := service.keys[:service.sorted]
+	tail := service.keys[service.sorted:]
+	sort.Strings(tail)
+
+	keys := make([]string, 0, len(service.db))
+	add := func(k string) {
+		if _, ok := service.db[k]; !ok {
+			return
+		}
+		// a key removed then put again is in both
+		if n := len(keys); n > 0 && keys[n-1] == k {
+			return
+		}
+		keys = append(keys, k)
+	}
+	i, j := 0, 0
+	for i < len(head) || j < len(tail) {
+		if j == len(tail) || (i < len(head) && head[i] <= tail[j]) {
+			add(head[i])
+			i++
+		} else {
+			add(tail[j])
+			j++
+		}
+	}
+	service.keys = keys
+	service.sorted = len(keys)
+	service.removed = false
+}
+
 func (service *MemoryService) PutData(key []byte, value []byte) error {
-	db := service.db
+	service.mu.Lock()
+	defer service.mu.Unlock()
 
-	s := hex.EncodeToString(key)
-	db.Store(s, value)
+	service.put(key, value)
 	return nil
 }
 
 func (service *MemoryService) PutDatas(keys [][]byte, values [][]byte) error {
-	db := service.db
-
 	lk := len(keys)
 	lv := len(values)
 	if lk != lv {
 		return errors.New("length error")
 	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
 	for i := 0; i < lk; i++ {
-		s := hex.EncodeToString(keys[i])
-		db.Store(s, values[i])
+		service.put(keys[i], values[i])
 	}
 	return nil
 }
 
 func (service *MemoryService) Flush() error {
-	service.db = &sync.Map{}
 	return nil
 }
 
+// Save writes the data to the snapshot file, if Path is set.
+func (service *MemoryService) Save() error {
+	return service.save()
+}
+
 func (service *MemoryService) GetData(key []byte) ([]byte, error) {
-	db := service.db
+	service.mu.RLock()
+	defer service.mu.RUnlock()
 
-	s := hex.EncodeToString(key)
-	value, ok := db.Load(s)
+	value, ok := service.db[string(key)]
 	if ok {
-		return value.([]byte), nil
+		return append([]byte(nil), value...), nil
 	}
 	return nil, nil
 }
 
 func (service *MemoryService) GetDatas(keys [][]byte) ([][]byte, error) {
-	db := service.db
+	service.mu.RLock()
+	defer service.mu.RUnlock()
 
 	l := len(keys)
 	bytes := make([][]byte, l)
 	for i := 0; i < l; i++ {
-		s := hex.EncodeToString(keys[i])
-		value, ok := db.Load(s)
+		value, ok := service.db[string(keys[i])]
 		if ok {
-			bytes[i] = value.([]byte)
+			bytes[i] = append([]byte(nil), value...)
 		} else {
 			bytes[i] = nil
 		}
@@ -84,18 +155,14 @@ func (service *MemoryService) GetDatas(keys [][]byte) ([][]byte, error) {
 }
 
 func (service *MemoryService) HasData(key []byte) bool {
-	db := service.db
+	service.mu.RLock()
+	defer service.mu.RUnlock()
 
-	s := hex.EncodeToString(key)
-	value, ok := db.Load(s)
+	value, ok := service.db[string(key)]
 	if !ok {
 		return false
 	}
-	if value == nil {
-		return false
-	}
-	data := value.([]byte)
-	if len(data) == 0 {
+	if len(value) == 0 {
 		return false
 	}
 
@@ -103,36 +170,173 @@ func (service *MemoryService) HasData(key []byte) bool {
 }
 
 func (service *MemoryService) RemoveData(key []byte) error {
-	db := service.db
+	service.mu.Lock()
+	defer service.mu.Unlock()
 
-	s := hex.EncodeToString(key)
-	db.Delete(s)
+	s := string(key)
+	if _, ok := service.db[s]; !ok {
+		return nil
+	}
+	delete(service.db, s)
+	service.removed = true
 	return nil
 }
 
+// ListData walks all entries in ascending key order. The callback sees a
+// consistent view taken when ListData was called, so it may write back to
+// the service.
 func (service *MemoryService) ListData(each func(key []byte, value []byte) error) error {
-	db := service.db
+	service.mu.Lock()
+	service.sortKeys()
+	keys := make([]string, len(service.keys))
+	copy(keys, service.keys)
+	values := make([][]byte, len(keys))
+	for i, k := range keys {
+		values[i] = service.db[k]
+	}
+	service.mu.Unlock()
 
-	db.Range(func(k, v interface{}) bool {
-		var key, value []byte
+	for i, k := range keys {
+		err := each([]byte(k), append([]byte(nil), values[i]...))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		if k != nil {
-			key = k.([]byte)
-		} else {
-			key = nil
+func (service *MemoryService) Stats() (*StoreStats, error) {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	size := int64(0)
+	for k, v := range service.db {
+		size += int64(len(k) + len(v))
+	}
+	return &StoreStats{
+		Keys:  uint64(len(service.db)),
+		Exact: true,
+		Size:  size,
+	}, nil
+}
+
+func (service *MemoryService) Compact(prefix []byte) error {
+	return nil
+}
+
+func (service *MemoryService) Snapshot() (Snapshot, error) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	service.sortKeys()
+	snapshot := &MemoryService{
+		db:     make(map[string][]byte, len(service.db)),
+		keys:   make([]string, len(service.keys)),
+		sorted: len(service.keys),
+	}
+	copy(snapshot.keys, service.keys)
+	for k, v := range service.db {
+		snapshot.db[k] = v
+	}
+	return &memorySnapshot{snapshot}, nil
+}
+
+type memorySnapshot struct {
+	service *MemoryService
+}
+
+func (s *memorySnapshot) ListData(each func(key []byte, value []byte) error) error {
+	return s.service.ListData(each)
+}
+
+func (s *memorySnapshot) Release() {
+	s.service = nil
+}
+
+func (service *MemoryService) load() error {
+	if len(service.Path) == 0 {
+		return nil
+	}
+	f, err := os.Open(service.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
 		}
+		return err
+	}
+	defer f.Close()
 
-		if v != nil {
-			value = v.([]byte)
-		} else {
-			value = nil
+	r := bufio.NewReader(f)
+	magic := make([]byte, len(snapshotMagic))
+	_, err = io.ReadFull(r, magic)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(magic, snapshotMagic) {
+		return errors.New("error snapshot format")
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	for {
+		key, value, err := ReadEntry(r)
+		if err == io.EOF {
+			return nil
 		}
+		if err != nil {
+			return err
+		}
+		service.put(key, value)
+	}
+}
+
+func (service *MemoryService) save() error {
+	if len(service.Path) == 0 {
+		return nil
+	}
+
+	tmpPath := service.Path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+
+	err = service.writeSnapshot(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, service.Path)
+}
 
-		err := each(key, value)
+func (service *MemoryService) writeSnapshot(w io.Writer) error {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	service.sortKeys()
+	_, err := w.Write(snapshotMagic)
+	if err != nil {
+		return err
+	}
+	for _, k := range service.keys {
+		err = WriteEntry(w, []byte(k), service.db[k])
 		if err != nil {
-			return false
+			return err
 		}
-		return true
-	})
+	}
 	return nil
 }
+
+var snapshotMagic = []byte("CHAINMEM1")
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	. "github.com/tokentransfer/check"
+)
+
+type MemorySuite struct{}
+
+func Test_Memory(t *testing.T) {
+	s := Suite(&MemorySuite{})
+	TestingRun(t, s)
+}
+
+func (suite *MemorySuite) TestListData(c *C) {
+	service := &MemoryService{}
+	err := service.Init(nil)
+	c.Assert(err, IsNil)
+
+	err = service.PutDatas([][]byte{[]byte("c"), []byte("a"), {0xff, 0x00}}, [][]byte{[]byte("3"), []byte("1"), []byte("4")})
+	c.Assert(err, IsNil)
+	err = service.PutData([]byte("b"), []byte("2"))
+	c.Assert(err, IsNil)
+	err = service.RemoveData([]byte("c"))
+	c.Assert(err, IsNil)
+	err = service.RemoveData([]byte("a"))
+	c.Assert(err, IsNil)
+	err = service.PutData([]byte("a"), []byte("1"))
+	c.Assert(err, IsNil)
+
+	keys := make([]string, 0)
+	values := make([]string, 0)
+	err = service.ListData(func(key []byte, value []byte) error {
+		keys = append(keys, string(key))
+		values = append(values, string(value))
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(keys, DeepEquals, []string{"a", "b", string([]byte{0xff, 0x00})})
+	c.Assert(values, DeepEquals, []string{"1", "2", "4"})
+}
+
+func (suite *MemorySuite) TestSnapshot(c *C) {
+	p := path.Join(c.MkDir(), "memory.snapshot")
+
+	service := &MemoryService{Path: p}
+	err := service.Init(nil)
+	c.Assert(err, IsNil)
+	err = service.PutData([]byte("key"), []byte("value"))
+	c.Assert(err, IsNil)
+	err = service.Flush()
+	c.Assert(err, IsNil)
+	_, err = os.Stat(p)
+	c.Assert(os.IsNotExist(err), Equals, true)
+	err = service.Close()
+	c.Assert(err, IsNil)
+
+	restored := &MemoryService{Path: p}
+	err = restored.Init(nil)
+	c.Assert(err, IsNil)
+	value, err := restored.GetData([]byte("key"))
+	c.Assert(err, IsNil)
+	c.Assert(string(value), Equals, "value")
+	c.Assert(restored.HasData([]byte("missing")), Equals, false)
+}
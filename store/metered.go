@@ -0,0 +1,129 @@
+package store
+
+import (
+	"time"
+
+	"github.com/tokentransfer/chain/metrics"
+
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+type opMetrics struct {
+	count   *metrics.Counter
+	errors  *metrics.Counter
+	latency *metrics.Histogram
+}
+
+func newOpMetrics(r *metrics.Registry, name string, op string) *opMetrics {
+	return &opMetrics{
+		count:   r.Counter("store_operations_total", "Number of store operations.", "store", name, "op", op),
+		errors:  r.Counter("store_errors_total", "Number of failed store operations.", "store", name, "op", op),
+		latency: r.Histogram("store_operation_seconds", "Latency of store operations.", "store", name, "op", op),
+	}
+}
+
+func (m *opMetrics) done(start time.Time, err error) {
+	m.count.Inc()
+	if err != nil {
+		m.errors.Inc()
+	}
+	m.latency.ObserveSince(start)
+}
+
+// MeteredService records the count, errors and latency of the operations on
+// another KvService, labelled with the store name.
+type MeteredService struct {
+	libstore.KvService
+
+	get    *opMetrics
+	put    *opMetrics
+	remove *opMetrics
+	list   *opMetrics
+
+	bytesRead    *metrics.Counter
+	bytesWritten *metrics.Counter
+}
+
+func NewMeteredService(service libstore.KvService, r *metrics.Registry, name string) *MeteredService {
+	if ls, ok := service.(*LevelService); ok {
+		ls.RegisterMetrics(r)
+	}
+	return &MeteredService{
+		KvService: service,
+
+		get:    newOpMetrics(r, name, "get"),
+		put:    newOpMetrics(r, name, "put"),
+		remove: newOpMetrics(r, name, "remove"),
+		list:   newOpMetrics(r, name, "list"),
+
+		bytesRead:    r.Counter("store_read_bytes_total", "Bytes read from the store.", "store", name),
+		bytesWritten: r.Counter("store_written_bytes_total", "Bytes written to the store.", "store", name),
+	}
+}
+
+func (service *MeteredService) Unwrap() libstore.KvService {
+	return service.KvService
+}
+
+func (service *MeteredService) PutData(key []byte, value []byte) error {
+	start := time.Now()
+	err := service.KvService.PutData(key, value)
+	service.put.done(start, err)
+	if err == nil {
+		service.bytesWritten.Add(uint64(len(key) + len(value)))
+	}
+	return err
+}
+
+func (service *MeteredService) PutDatas(keys [][]byte, values [][]byte) error {
+	start := time.Now()
+	err := service.KvService.PutDatas(keys, values)
+	service.put.done(start, err)
+	if err == nil {
+		size := 0
+		for i := range keys {
+			size += len(keys[i]) + len(values[i])
+		}
+		service.bytesWritten.Add(uint64(size))
+	}
+	return err
+}
+
+func (service *MeteredService) GetData(key []byte) ([]byte, error) {
+	start := time.Now()
+	value, err := service.KvService.GetData(key)
+	service.get.done(start, err)
+	service.bytesRead.Add(uint64(len(value)))
+	return value, err
+}
+
+func (service *MeteredService) GetDatas(keys [][]byte) ([][]byte, error) {
+	start := time.Now()
+	values, err := service.KvService.GetDatas(keys)
+	service.get.done(start, err)
+	for _, value := range values {
+		service.bytesRead.Add(uint64(len(value)))
+	}
+	return values, err
+}
+
+func (service *MeteredService) HasData(key []byte) bool {
+	start := time.Now()
+	ok := service.KvService.HasData(key)
+	service.get.done(start, nil)
+	return ok
+}
+
+func (service *MeteredService) RemoveData(key []byte) error {
+	start := time.Now()
+	err := service.KvService.RemoveData(key)
+	service.remove.done(start, err)
+	return err
+}
+
+func (service *MeteredService) ListData(each func(key []byte, value []byte) error) error {
+	start := time.Now()
+	err := service.KvService.ListData(each)
+	service.list.done(start, err)
+	return err
+}
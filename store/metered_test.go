@@ -0,0 +1,58 @@
+package store
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tokentransfer/chain/metrics"
+	"github.com/tokentransfer/chain/store/storetest"
+
+	. "github.com/tokentransfer/check"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+type MeteredSuite struct{}
+
+func Test_Metered(t *testing.T) {
+	s := Suite(&MeteredSuite{})
+	TestingRun(t, s)
+}
+
+func (suite *MeteredSuite) TestConformance(c *C) {
+	storetest.Run(c, func() libstore.KvService {
+		service, err := NewKvService(MEMORY, "test", nil)
+		c.Assert(err, IsNil)
+		return NewMeteredService(service, metrics.NewRegistry(), "test")
+	})
+}
+
+func (suite *MeteredSuite) TestExport(c *C) {
+	r := metrics.NewRegistry()
+	db, err := NewKvService(MEMORY, "test", nil)
+	c.Assert(err, IsNil)
+	service := NewMeteredService(db, r.With("service", "node"), "block")
+
+	err = service.PutData([]byte("key"), []byte("value"))
+	c.Assert(err, IsNil)
+	_, err = service.GetData([]byte("key"))
+	c.Assert(err, IsNil)
+	_, err = service.GetData([]byte("missing"))
+	c.Assert(err, IsNil)
+
+	buf := &bytes.Buffer{}
+	err = r.WriteText(buf)
+	c.Assert(err, IsNil)
+	for _, line := range []string{
+		`store_operations_total{service="node",store="block",op="get"} 2`,
+		`store_operations_total{service="node",store="block",op="put"} 1`,
+		`store_errors_total{service="node",store="block",op="get"} 0`,
+		`store_read_bytes_total{service="node",store="block"} 5`,
+		`store_written_bytes_total{service="node",store="block"} 8`,
+		`store_operation_seconds_count{service="node",store="block",op="get"} 2`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			c.Fatalf("missing %q in\n%s", line, buf.String())
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package store
+
+import (
+	"errors"
+	"path"
+	"sync"
+
+	"github.com/tokentransfer/interfaces/core"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+const (
+	NAMESPACED = "namespaced"
+
+	NAMESPACED_DB = "chain"
+)
+
+func init() {
+	Register(NAMESPACED, func(name string, c core.Config) (libstore.KvService, error) {
+		return &PrefixService{Name: name}, nil
+	})
+}
+
+type sharedLevel struct {
+	service *LevelService
+	refs    int
+}
+
+var (
+	sharedLock   sync.Mutex
+	sharedLevels = map[string]*sharedLevel{}
+)
+
+// PrefixService keeps a store in a leveldb shared by all stores of the data
+// directory, with every key prefixed by the store name.
+type PrefixService struct {
+	Name string
+
+	dbPath string
+	level  *LevelService
+	prefix []byte
+}
+
+func (service *PrefixService) Init(c core.Config) error {
+	if c == nil {
+		return errors.New("no config for namespaced leveldb")
+	}
+	service.dbPath = path.Join(c.GetDataDir(), NAMESPACED_DB)
+	service.prefix = []byte(service.Name + "/")
+
+	sharedLock.Lock()
+	defer sharedLock.Unlock()
+
+	shared, ok := sharedLevels[service.dbPath]
+	if !ok {
+		level := &LevelService{Path: service.dbPath}
+		err := level.Init(nil)
+		if err != nil {
+			return err
+		}
+		shared = &sharedLevel{service: level}
+		sharedLevels[service.dbPath] = shared
+	}
+	shared.refs++
+	service.level = shared.service
+	return nil
+}
+
+func (service *PrefixService) Start() error {
+	return nil
+}
+
+func (service *PrefixService) Close() error {
+	if service.level == nil {
+		return nil
+	}
+	service.level = nil
+
+	sharedLock.Lock()
+	defer sharedLock.Unlock()
+
+	shared, ok := sharedLevels[service.dbPath]
+	if !ok {
+		return nil
+	}
+	shared.refs--
+	if shared.refs > 0 {
+		return nil
+	}
+	delete(sharedLevels, service.dbPath)
+	return shared.service.Close()
+}
+
+func (service *PrefixService) key(key []byte) []byte {
+	k := make([]byte, 0, len(service.prefix)+len(key))
+	k = append(k, service.prefix...)
+	return append(k, key...)
+}
+
+func (service *PrefixService) PutData(key []byte, value []byte) error {
+	return service.level.PutData(service.key(key), value)
+}
+
+func (service *PrefixService) PutDatas(keys [][]byte, values [][]byte) error {
+	if len(keys) != len(values) {
+		return errors.New("length error")
+	}
+	ks := make([][]byte, len(keys))
+	for i, key := range keys {
+		ks[i] = service.key(key)
+	}
+	return service.level.PutDatas(ks, values)
+}
+
+func (service *PrefixService) Flush() error {
+	return service.level.Flush()
+}
+
+func (service *PrefixService) GetData(key []byte) ([]byte, error) {
+	return service.level.GetData(service.key(key))
+}
+
+func (service *PrefixService) GetDatas(keys [][]byte) ([][]byte, error) {
+	ks := make([][]byte, len(keys))
+	for i, key := range keys {
+		ks[i] = service.key(key)
+	}
+	return service.level.GetDatas(ks)
+}
+
+func (service *PrefixService) HasData(key []byte) bool {
+	return service.level.HasData(service.key(key))
+}
+
+func (service *PrefixService) RemoveData(key []byte) error {
+	return service.level.RemoveData(service.key(key))
+}
+
+func (service *PrefixService) ListData(each func(key []byte, value []byte) error) error {
+	l := len(service.prefix)
+	return service.level.ListPrefix(service.prefix, func(key []byte, value []byte) error {
+		return each(key[l:], value)
+	})
+}
+
+func (service *PrefixService) Snapshot() (Snapshot, error) {
+	s, err := service.level.SnapshotPrefix(service.prefix)
+	if err != nil {
+		return nil, err
+	}
+	return &prefixSnapshot{s, len(service.prefix)}, nil
+}
+
+type prefixSnapshot struct {
+	Snapshot
+
+	l int
+}
+
+func (s *prefixSnapshot) ListData(each func(key []byte, value []byte) error) error {
+	return s.Snapshot.ListData(func(key []byte, value []byte) error {
+		return each(key[s.l:], value)
+	})
+}
+
+func (service *PrefixService) Compact(prefix []byte) error {
+	return service.level.Compact(service.key(prefix))
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/tokentransfer/chain/store/storetest"
+
+	. "github.com/tokentransfer/check"
+	"github.com/tokentransfer/interfaces/core"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+type PrefixSuite struct{}
+
+func Test_Prefix(t *testing.T) {
+	s := Suite(&PrefixSuite{})
+	TestingRun(t, s)
+}
+
+type dirConfig struct {
+	core.Config
+
+	dir string
+}
+
+func (c *dirConfig) GetDataDir() string {
+	return c.dir
+}
+
+func (suite *PrefixSuite) TestConformance(c *C) {
+	storetest.Run(c, func() libstore.KvService {
+		// a neighbour store in the same leveldb must not show through
+		config := &dirConfig{dir: c.MkDir()}
+		other, err := NewKvService(NAMESPACED, "other", config)
+		c.Assert(err, IsNil)
+		err = other.PutData([]byte("key-0"), []byte("other"))
+		c.Assert(err, IsNil)
+
+		service, err := NewKvService(NAMESPACED, "test", config)
+		c.Assert(err, IsNil)
+		c.Assert(other.Close(), IsNil)
+		return service
+	})
+}
+
+func (suite *PrefixSuite) TestShared(c *C) {
+	config := &dirConfig{dir: c.MkDir()}
+	a, err := NewKvService(NAMESPACED, "a", config)
+	c.Assert(err, IsNil)
+	b, err := NewKvService(NAMESPACED, "b", config)
+	c.Assert(err, IsNil)
+
+	err = a.PutData([]byte("key"), []byte("1"))
+	c.Assert(err, IsNil)
+	value, err := b.GetData([]byte("key"))
+	c.Assert(err, IsNil)
+	c.Assert(value, IsNil)
+
+	c.Assert(a.Close(), IsNil)
+	value, err = b.GetData([]byte("key"))
+	c.Assert(err, IsNil)
+	c.Assert(value, IsNil)
+	c.Assert(b.Close(), IsNil)
+
+	a, err = NewKvService(NAMESPACED, "a", config)
+	c.Assert(err, IsNil)
+	defer a.Close()
+	value, err = a.GetData([]byte("key"))
+	c.Assert(err, IsNil)
+	c.Assert(string(value), Equals, "1")
+}
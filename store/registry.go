@@ -0,0 +1,106 @@
+package store
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/tokentransfer/interfaces/core"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+const (
+	LEVELDB  = "leveldb"
+	MEMORY   = "memory"
+	SNAPSHOT = "snapshot"
+
+	DEFAULT_BACKEND = LEVELDB
+)
+
+// BackendConfig is implemented by configs which select the storage backend.
+type BackendConfig interface {
+	GetStoreBackend() string
+}
+
+// Factory creates an uninitialized KvService for the store with the given
+// name, e.g. "index" or "block". The caller is responsible for Init and Start.
+type Factory func(name string, c core.Config) (libstore.KvService, error)
+
+var (
+	backendsLock sync.RWMutex
+	backends     = map[string]Factory{}
+)
+
+func init() {
+	Register(LEVELDB, func(name string, c core.Config) (libstore.KvService, error) {
+		return &LevelService{Name: name}, nil
+	})
+	Register(MEMORY, func(name string, c core.Config) (libstore.KvService, error) {
+		return &MemoryService{Name: name}, nil
+	})
+	Register(SNAPSHOT, func(name string, c core.Config) (libstore.KvService, error) {
+		if c == nil {
+			return nil, fmt.Errorf("no config for %s backend", SNAPSHOT)
+		}
+		p := path.Join(c.GetDataDir(), name+".snapshot")
+		return &MemoryService{Name: name, Path: p}, nil
+	})
+}
+
+// Register makes a backend available by name. Registering the same name twice
+// replaces the previous factory.
+func Register(backend string, f Factory) {
+	backendsLock.Lock()
+	defer backendsLock.Unlock()
+
+	backends[backend] = f
+}
+
+// Backends returns the sorted names of all registered backends.
+func Backends() []string {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+
+	list := make([]string, 0, len(backends))
+	for name := range backends {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// GetBackend returns the backend selected by the config, or DEFAULT_BACKEND.
+func GetBackend(c core.Config) string {
+	if bc, ok := c.(BackendConfig); ok {
+		backend := bc.GetStoreBackend()
+		if len(backend) > 0 {
+			return backend
+		}
+	}
+	return DEFAULT_BACKEND
+}
+
+// NewKvService creates, initializes and starts the named store on the backend.
+func NewKvService(backend string, name string, c core.Config) (libstore.KvService, error) {
+	backendsLock.RLock()
+	f, ok := backends[backend]
+	backendsLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("can't find backend: %s", backend)
+	}
+
+	service, err := f(name, c)
+	if err != nil {
+		return nil, err
+	}
+	err = service.Init(c)
+	if err != nil {
+		return nil, err
+	}
+	err = service.Start()
+	if err != nil {
+		return nil, err
+	}
+	return service, nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/tokentransfer/chain/store/storetest"
+
+	. "github.com/tokentransfer/check"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+type RegistrySuite struct{}
+
+func Test_Registry(t *testing.T) {
+	s := Suite(&RegistrySuite{})
+	TestingRun(t, s)
+}
+
+func (suite *RegistrySuite) TestBackends(c *C) {
+	c.Assert(Backends(), DeepEquals, []string{LEVELDB, MEMORY, NAMESPACED, SNAPSHOT})
+	c.Assert(GetBackend(nil), Equals, DEFAULT_BACKEND)
+
+	_, err := NewKvService("unknown", "index", nil)
+	c.Assert(err, NotNil)
+}
+
+func (suite *RegistrySuite) TestLevelConformance(c *C) {
+	storetest.Run(c, func() libstore.KvService {
+		service := &LevelService{Path: c.MkDir()}
+		err := service.Init(nil)
+		c.Assert(err, IsNil)
+		return service
+	})
+}
+
+func (suite *RegistrySuite) TestMemoryConformance(c *C) {
+	storetest.Run(c, func() libstore.KvService {
+		service, err := NewKvService(MEMORY, "test", nil)
+		c.Assert(err, IsNil)
+		return service
+	})
+}
+
+func (suite *RegistrySuite) TestLevelStats(c *C) {
+	service := &LevelService{Path: c.MkDir()}
+	err := service.Init(nil)
+	c.Assert(err, IsNil)
+	defer service.Close()
+
+	for i := 0; i < 10; i++ {
+		err = service.PutData([]byte{byte(i)}, []byte("value"))
+		c.Assert(err, IsNil)
+	}
+	err = service.RemoveData([]byte{0})
+	c.Assert(err, IsNil)
+	err = service.Compact(nil)
+	c.Assert(err, IsNil)
+
+	stats, err := service.Stats()
+	c.Assert(err, IsNil)
+	c.Assert(stats.Keys, Equals, uint64(9))
+	c.Assert(stats.Exact, Equals, true)
+	c.Assert(stats.Size > 0, Equals, true)
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"encoding/binary"
+	"io"
+
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+// Snapshot is a consistent read-only view of a KvService.
+type Snapshot interface {
+	ListData(each func(key []byte, value []byte) error) error
+	Release()
+}
+
+// Snapshotter is implemented by KvServices which can take a Snapshot while
+// they are being written.
+type Snapshotter interface {
+	Snapshot() (Snapshot, error)
+}
+
+// Wrapper is implemented by KvServices which decorate another KvService.
+type Wrapper interface {
+	Unwrap() libstore.KvService
+}
+
+// Unwrap returns the innermost KvService, the one which holds the data as it
+// is stored.
+func Unwrap(service libstore.KvService) libstore.KvService {
+	for {
+		w, ok := service.(Wrapper)
+		if !ok {
+			return service
+		}
+		service = w.Unwrap()
+	}
+}
+
+// WriteEntry writes a key and value as two length-prefixed byte strings.
+func WriteEntry(w io.Writer, key []byte, value []byte) error {
+	err := writeEntryBytes(w, key)
+	if err != nil {
+		return err
+	}
+	return writeEntryBytes(w, value)
+}
+
+// ReadEntry reads a key and value written by WriteEntry. It returns io.EOF
+// when there are no more entries.
+func ReadEntry(r io.Reader) ([]byte, []byte, error) {
+	key, err := readEntryBytes(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err := readEntryBytes(r)
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		return nil, nil, err
+	}
+	return key, value, nil
+}
+
+func writeEntryBytes(w io.Writer, b []byte) error {
+	err := binary.Write(w, binary.LittleEndian, uint32(len(b)))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func readEntryBytes(r io.Reader) ([]byte, error) {
+	l := uint32(0)
+	err := binary.Read(r, binary.LittleEndian, &l)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, l)
+	_, err = io.ReadFull(r, b)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
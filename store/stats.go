@@ -0,0 +1,20 @@
+package store
+
+// StoreStats describes the size of a store.
+type StoreStats struct {
+	Keys       uint64  // number of keys, estimated unless Exact
+	Exact      bool    // whether Keys is exact
+	Size       int64   // bytes used, on disk for persistent stores
+	LevelSizes []int64 // bytes by level, for leveled stores
+}
+
+// Statter is implemented by KvServices which report their size.
+type Statter interface {
+	Stats() (*StoreStats, error)
+}
+
+// Compacter is implemented by KvServices which can reclaim the space of
+// removed data, for the keys with a prefix or all keys if it is empty.
+type Compacter interface {
+	Compact(prefix []byte) error
+}
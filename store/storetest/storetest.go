@@ -0,0 +1,120 @@
+// Package storetest is a conformance suite for KvService implementations.
+// Every backend registered with store.Register is expected to pass it.
+package storetest
+
+import (
+	"fmt"
+	"sort"
+
+	. "github.com/tokentransfer/check"
+	libstore "github.com/tokentransfer/interfaces/store"
+)
+
+// Run checks the behaviour shared by all KvService implementations. The
+// service returned by open must be initialized, started and empty.
+func Run(c *C, open func() libstore.KvService) {
+	run(c, open, true)
+}
+
+// RunUnordered is Run for services whose ListData doesn't walk the keys in
+// order, such as a CryptService with encrypted keys.
+func RunUnordered(c *C, open func() libstore.KvService) {
+	run(c, open, false)
+}
+
+func run(c *C, open func() libstore.KvService, ordered bool) {
+	testPutGet(c, open())
+	testPutDatas(c, open())
+	testRemove(c, open())
+	testListData(c, open(), ordered)
+}
+
+func testPutGet(c *C, service libstore.KvService) {
+	defer service.Close()
+
+	value, err := service.GetData([]byte("missing"))
+	c.Assert(err, IsNil)
+	c.Assert(value, IsNil)
+	c.Assert(service.HasData([]byte("missing")), Equals, false)
+
+	err = service.PutData([]byte("key"), []byte("value"))
+	c.Assert(err, IsNil)
+	value, err = service.GetData([]byte("key"))
+	c.Assert(err, IsNil)
+	c.Assert(string(value), Equals, "value")
+	c.Assert(service.HasData([]byte("key")), Equals, true)
+
+	err = service.PutData([]byte("key"), []byte("other"))
+	c.Assert(err, IsNil)
+	value, err = service.GetData([]byte("key"))
+	c.Assert(err, IsNil)
+	c.Assert(string(value), Equals, "other")
+
+	err = service.PutData([]byte("empty"), []byte{})
+	c.Assert(err, IsNil)
+	c.Assert(service.HasData([]byte("empty")), Equals, false)
+}
+
+func testPutDatas(c *C, service libstore.KvService) {
+	defer service.Close()
+
+	err := service.PutDatas([][]byte{[]byte("a")}, [][]byte{})
+	c.Assert(err, NotNil)
+
+	err = service.PutDatas([][]byte{[]byte("a"), []byte("b")}, [][]byte{[]byte("1"), []byte("2")})
+	c.Assert(err, IsNil)
+	values, err := service.GetDatas([][]byte{[]byte("b"), []byte("a")})
+	c.Assert(err, IsNil)
+	c.Assert(len(values), Equals, 2)
+	c.Assert(string(values[0]), Equals, "2")
+	c.Assert(string(values[1]), Equals, "1")
+}
+
+func testRemove(c *C, service libstore.KvService) {
+	defer service.Close()
+
+	err := service.PutData([]byte("key"), []byte("value"))
+	c.Assert(err, IsNil)
+	err = service.RemoveData([]byte("key"))
+	c.Assert(err, IsNil)
+	c.Assert(service.HasData([]byte("key")), Equals, false)
+	value, err := service.GetData([]byte("key"))
+	c.Assert(err, IsNil)
+	c.Assert(value, IsNil)
+
+	err = service.RemoveData([]byte("missing"))
+	c.Assert(err, IsNil)
+}
+
+func testListData(c *C, service libstore.KvService, ordered bool) {
+	defer service.Close()
+
+	expected := make([]string, 0)
+	for i := 9; i >= 0; i-- {
+		key := fmt.Sprintf("key-%d", i)
+		err := service.PutData([]byte(key), []byte(fmt.Sprintf("value-%d", i)))
+		c.Assert(err, IsNil)
+		expected = append([]string{key}, expected...)
+	}
+
+	keys := make([]string, 0)
+	err := service.ListData(func(key []byte, value []byte) error {
+		c.Assert(string(value), Equals, "value-"+string(key[len("key-"):]))
+		keys = append(keys, string(key))
+		return nil
+	})
+	c.Assert(err, IsNil)
+	if !ordered {
+		sort.Strings(keys)
+	}
+	c.Assert(keys, DeepEquals, expected)
+
+	stop := fmt.Errorf("stop")
+	count := 0
+	err = service.ListData(func(key []byte, value []byte) error {
+		count++
+		return stop
+	})
+	c.Assert(err, Equals, stop)
+	c.Assert(count, Equals, 1)
+}